@@ -0,0 +1,209 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/charmbracelet/bubbles/key"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+	"github.com/charmbracelet/x/ansi"
+)
+
+// ============================================================
+// ACCESSIBILITY
+// ============================================================
+
+// asciiMode replaces emoji and box-drawing characters with plain ASCII and
+// text labels. Set it with setASCII so the styles pick up ASCII borders.
+var asciiMode bool
+
+func setASCII(on bool) {
+	asciiMode = on
+	applyTheme(theme)
+}
+
+// glyph returns fancy normally and plain in ASCII mode.
+func glyph(fancy, plain string) string {
+	if asciiMode {
+		return plain
+	}
+	return fancy
+}
+
+func roundedBorder() lipgloss.Border {
+	if asciiMode {
+		return lipgloss.ASCIIBorder()
+	}
+	return lipgloss.RoundedBorder()
+}
+
+func normalBorder() lipgloss.Border {
+	if asciiMode {
+		return lipgloss.ASCIIBorder()
+	}
+	return lipgloss.NormalBorder()
+}
+
+// heatmapSymbol is the two-character cell for a day. The ASCII symbols
+// differ in shape, so they read without color.
+func heatmapSymbol(done bool) string {
+	if done {
+		return glyph("██", "##")
+	}
+	return glyph("░░", "..")
+}
+
+// writeSummary writes a linear, plain-text account of every habit: one
+// sentence per fact, no tables, symbols or color, for screen readers.
+func writeSummary(w io.Writer, db *Database, now time.Time) error {
+	habits, err := db.GetHabits()
+	if err != nil {
+		return err
+	}
+
+	categories, err := db.GetCategories()
+	if err != nil {
+		return err
+	}
+	categoryNames := make(map[int]string)
+	for _, c := range categories {
+		categoryNames[c.ID] = c.Name
+	}
+
+	done, err := db.GetDoneOn(now.Format("2006-01-02"))
+	if err != nil {
+		return err
+	}
+
+	logs, err := db.GetLogsSince("")
+	if err != nil {
+		return err
+	}
+
+	fmt.Fprintf(w, "Habit summary for %s.\n", now.Format("Monday, January 2, 2006"))
+	if len(habits) == 0 {
+		fmt.Fprintln(w, "No habits yet.")
+		return nil
+	}
+
+	var pending []string
+	for _, h := range habits {
+		if !done[h.ID] {
+			pending = append(pending, h.Name)
+		}
+	}
+	fmt.Fprintf(w, "%d of %d habits done today.", len(habits)-len(pending), len(habits))
+	if len(pending) > 0 {
+		fmt.Fprintf(w, " Pending: %s.", strings.Join(pending, ", "))
+	}
+	fmt.Fprintln(w)
+
+	for i, h := range habits {
+		fmt.Fprintln(w)
+		fmt.Fprintf(w, "%d. %s.\n", i+1, h.Name)
+		if done[h.ID] {
+			fmt.Fprintln(w, "Done today.")
+		} else {
+			fmt.Fprintln(w, "Not done today.")
+		}
+		fmt.Fprintf(w, "Current streak: %s.\n", plural(h.CurrentStreak, "day"))
+		strength, trend := liveStrength(logs[h.ID], now)
+		fmt.Fprintf(w, "Strength: %.0f%%, %s.\n", strength, describeTrend(trend))
+		fmt.Fprintf(w, "Total completions: %d.\n", h.TotalDone)
+		fmt.Fprintf(w, "Level %d, %d XP, %s.\n", h.Level, h.XP, plural(h.Coins, "coin"))
+		if name, ok := categoryNames[h.CategoryID]; ok {
+			fmt.Fprintf(w, "Category: %s.\n", name)
+		}
+		if len(h.Tags) > 0 {
+			fmt.Fprintf(w, "Tags: %s.\n", strings.Join(h.Tags, ", "))
+		}
+
+		unlocked, err := db.GetAchievements(h.ID)
+		if err != nil {
+			return err
+		}
+		var achievements []string
+		for _, u := range unlocked {
+			achievements = append(achievements, strings.TrimSuffix(achievementName(u.Type), "!"))
+		}
+		if len(achievements) > 0 {
+			fmt.Fprintf(w, "Achievements: %s.\n", strings.Join(achievements, ", "))
+		}
+	}
+
+	return nil
+}
+
+func plural(n int, unit string) string {
+	if n == 1 {
+		return fmt.Sprintf("%d %s", n, unit)
+	}
+	return fmt.Sprintf("%d %ss", n, unit)
+}
+
+// runSummary implements `habit summary`.
+func runSummary(args []string) error {
+	fset := flag.NewFlagSet("summary", flag.ContinueOnError)
+	if err := fset.Parse(args); err != nil {
+		return err
+	}
+
+	// For the names of configured achievements
+	if _, err := loadConfig(); err != nil {
+		return err
+	}
+
+	db, err := NewDatabase()
+	if err != nil {
+		return err
+	}
+	defer db.Close()
+
+	return writeSummary(os.Stdout, db, time.Now())
+}
+
+func (m *Model) updateSummary(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	switch {
+	case key.Matches(msg, m.keys.Back, m.keys.Summary):
+		m.mode = modeList
+		m.summaryTop = 0
+
+	case key.Matches(msg, m.keys.Up):
+		m.summaryTop = max(m.summaryTop-1, 0)
+
+	case key.Matches(msg, m.keys.Down):
+		m.summaryTop++
+	}
+	return m, nil
+}
+
+// viewSummary is shown without the surrounding box so it reads top to
+// bottom as plain lines, scrolled a line at a time when it does not fit.
+func (m *Model) viewSummary() string {
+	var b strings.Builder
+	if err := writeSummary(&b, m.db, time.Now()); err != nil {
+		return "Error: " + err.Error()
+	}
+
+	text := strings.TrimSuffix(b.String(), "\n")
+	if m.width > 0 {
+		text = ansi.Wrap(text, m.width, "")
+	}
+	lines := strings.Split(text, "\n")
+	back := m.keys.Back.Help().Key + " or " + m.keys.Summary.Help().Key
+	footer := "Press " + back + " to return to the list."
+
+	if m.height > 2 && len(lines) > m.height-2 {
+		view := m.height - 2
+		m.summaryTop = min(m.summaryTop, len(lines)-view)
+		lines = lines[m.summaryTop : m.summaryTop+view]
+		footer = "Press " + m.keys.Down.Help().Key + " or " + m.keys.Up.Help().Key + " to scroll, " + back + " to return to the list."
+	}
+
+	return strings.Join(lines, "\n") + "\n\n" + footer
+}
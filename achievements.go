@@ -0,0 +1,584 @@
+package main
+
+import (
+	"database/sql"
+	"fmt"
+	"math"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// ============================================================
+// ACHIEVEMENTS
+// ============================================================
+
+// achievementDef is an achievement; Type is what gets stored when it
+// unlocks. Rule is written in the language parseRule reads.
+type achievementDef struct {
+	Type   string
+	Icon   string
+	Name   string
+	Rule   string
+	Global bool // judged on all habits together rather than on each habit
+	conds  []condition
+}
+
+func (a achievementDef) Label() string {
+	return glyph(a.Icon+" ", "") + a.Name
+}
+
+var builtinAchievements = []achievementDef{
+	// Streak achievements
+	{Type: "streak_3", Icon: "🔥", Name: "3 Day Streak!", Rule: "streak >= 3"},
+	{Type: "streak_7", Icon: "⭐", Name: "Week Warrior!", Rule: "streak >= 7"},
+	{Type: "streak_30", Icon: "🏆", Name: "Monthly Master!", Rule: "streak >= 30"},
+	{Type: "streak_100", Icon: "👑", Name: "Century Champion!", Rule: "streak >= 100"},
+	{Type: "streak_365", Icon: "💎", Name: "Year Legend!", Rule: "streak >= 365"},
+
+	// Completion achievements
+	{Type: "total_10", Icon: "✨", Name: "Getting Started (10)", Rule: "total >= 10"},
+	{Type: "total_50", Icon: "🎯", Name: "Half Century (50)", Rule: "total >= 50"},
+	{Type: "total_100", Icon: "💪", Name: "Century Club (100)", Rule: "total >= 100"},
+	{Type: "total_365", Icon: "🌟", Name: "Year Round (365)", Rule: "total >= 365"},
+	{Type: "total_1000", Icon: "🚀", Name: "Thousand Strong (1000)", Rule: "total >= 1000"},
+
+	// Level achievements
+	{Type: "level_5", Icon: "🌻", Name: "Blooming (Level 5)", Rule: "level >= 5"},
+	{Type: "level_10", Icon: "🌳", Name: "Growing Strong (Level 10)", Rule: "level >= 10"},
+	{Type: "level_20", Icon: "👑", Name: "Habit Royalty (Level 20)", Rule: "level >= 20"},
+	{Type: "level_50", Icon: "🔥", Name: "Legendary (Level 50)", Rule: "level >= 50"},
+}
+
+// achievementDefs are the built-in achievements plus those from the config
+// file; set by setAchievements.
+var achievementDefs = mustCompileAchievements(builtinAchievements)
+
+// condition is one comparison of a rule, e.g. total[30d] >= 20.
+type condition struct {
+	Metric string
+	Window int // days, 0 for all time
+	Op     string
+	Value  float64
+}
+
+// Metrics a rule can use, whether each takes a [Nd] window, and whether it
+// only makes sense for all habits together.
+var ruleMetrics = map[string]struct{ window, needsWindow, global bool }{
+	"streak":       {},
+	"total":        {window: true},
+	"level":        {},
+	"xp":           {},
+	"strength":     {},
+	"perfect_days": {window: true, global: true},
+	"rate":         {window: true, needsWindow: true},
+}
+
+var conditionPattern = regexp.MustCompile(`^([a-z_]+)(?:\[(\d+)d\])?\s*(>=|<=|==|>|<)\s*(\d+(?:\.\d+)?)$`)
+
+// parseRule reads conditions joined by "and", each a metric, an optional
+// window of the last N days, a comparison and a number:
+//
+//	streak >= 7
+//	total[30d] >= 25 and level >= 3
+//	perfect_days[7d] >= 7
+func parseRule(rule string) ([]condition, error) {
+	var conds []condition
+	for _, part := range strings.Split(strings.ToLower(rule), " and ") {
+		part = strings.TrimSpace(part)
+		match := conditionPattern.FindStringSubmatch(part)
+		if match == nil {
+			return nil, fmt.Errorf("invalid condition %q (expected e.g. \"streak >= 7\" or \"total[30d] >= 20\")", part)
+		}
+
+		metric, ok := ruleMetrics[match[1]]
+		if !ok {
+			return nil, fmt.Errorf("unknown metric %q (use streak, total, level, xp, strength, perfect_days or rate)", match[1])
+		}
+
+		c := condition{Metric: match[1], Op: match[3]}
+		c.Value, _ = strconv.ParseFloat(match[4], 64)
+		if match[2] != "" {
+			if !metric.window {
+				return nil, fmt.Errorf("%s cannot have a time window", c.Metric)
+			}
+			c.Window, _ = strconv.Atoi(match[2])
+			if c.Window == 0 {
+				return nil, fmt.Errorf("%s: the window must be at least 1 day", c.Metric)
+			}
+		} else if metric.needsWindow {
+			return nil, fmt.Errorf("%s needs a time window, e.g. %s[30d]", c.Metric, c.Metric)
+		}
+
+		conds = append(conds, c)
+	}
+	return conds, nil
+}
+
+// compileAchievements parses the rules of defs, then adds the configured
+// achievements; one with a built-in's id replaces it, but two configured
+// ones may not share an id.
+func compileAchievements(defs []achievementDef, custom []AchievementConfig) ([]achievementDef, error) {
+	all := make([]achievementDef, 0, len(defs)+len(custom))
+	index := make(map[string]int)
+	for _, a := range defs {
+		index[a.Type] = len(all)
+		all = append(all, a)
+	}
+
+	seen := make(map[string]bool)
+	for i, c := range custom {
+		if c.ID == "" || c.Name == "" || c.Rule == "" {
+			return nil, fmt.Errorf("achievement %d: id, name and rule are required", i+1)
+		}
+		if seen[c.ID] {
+			return nil, fmt.Errorf("achievement %d: id %q is already defined", i+1, c.ID)
+		}
+		seen[c.ID] = true
+		a := achievementDef{Type: c.ID, Icon: c.Icon, Name: c.Name, Rule: c.Rule, Global: c.Global}
+		if a.Icon == "" {
+			a.Icon = "🏅"
+		}
+		if j, ok := index[a.Type]; ok {
+			all[j] = a
+			continue
+		}
+		index[a.Type] = len(all)
+		all = append(all, a)
+	}
+
+	for i := range all {
+		conds, err := parseRule(all[i].Rule)
+		if err != nil {
+			return nil, fmt.Errorf("achievement %q: %w", all[i].Type, err)
+		}
+		for _, c := range conds {
+			if ruleMetrics[c.Metric].global && !all[i].Global {
+				return nil, fmt.Errorf("achievement %q: %s looks at every habit, so it needs \"global\": true", all[i].Type, c.Metric)
+			}
+		}
+		all[i].conds = conds
+	}
+	return all, nil
+}
+
+func mustCompileAchievements(defs []achievementDef) []achievementDef {
+	all, err := compileAchievements(defs, nil)
+	if err != nil {
+		panic(err)
+	}
+	return all
+}
+
+// setAchievements adds the configured achievements to the built-in ones.
+func setAchievements(custom []AchievementConfig) error {
+	all, err := compileAchievements(builtinAchievements, custom)
+	if err != nil {
+		return err
+	}
+	achievementDefs = all
+	return nil
+}
+
+// achievementContext holds what rules are judged on: every habit and
+// every check-in.
+type achievementContext struct {
+	habits []Habit
+	logs   map[int]map[string]bool
+	starts map[int]string // first day each habit counts, as in GetHabitStarts
+	today  time.Time
+}
+
+func loadAchievementContext(tx *sql.Tx, now time.Time) (*achievementContext, error) {
+	ctx := &achievementContext{logs: make(map[int]map[string]bool), starts: make(map[int]string), today: startOfDay(now)}
+
+	rows, err := tx.Query("SELECT id FROM habits ORDER BY position, id")
+	if err != nil {
+		return nil, err
+	}
+	var ids []int
+	for rows.Next() {
+		var id int
+		if err := rows.Scan(&id); err != nil {
+			rows.Close()
+			return nil, err
+		}
+		ids = append(ids, id)
+	}
+	rows.Close()
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	for _, id := range ids {
+		h, err := scanHabit(tx, id)
+		if err != nil {
+			return nil, err
+		}
+		ctx.habits = append(ctx.habits, h)
+		ctx.logs[id] = make(map[string]bool)
+		ctx.starts[id] = h.CreatedAt[:min(len(h.CreatedAt), 10)]
+	}
+
+	rows, err = tx.Query("SELECT habit_id, date FROM logs")
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	for rows.Next() {
+		var id int
+		var date string
+		if err := rows.Scan(&id, &date); err != nil {
+			return nil, err
+		}
+		if ctx.logs[id] == nil {
+			continue // left behind by a deleted habit
+		}
+		ctx.logs[id][date] = true
+		if date < ctx.starts[id] {
+			ctx.starts[id] = date
+		}
+	}
+
+	return ctx, rows.Err()
+}
+
+// windowStart is the first day of a window of the last days days, or the
+// zero time for all time.
+func (c *achievementContext) windowStart(days int) time.Time {
+	if days == 0 {
+		return time.Time{}
+	}
+	return c.today.AddDate(0, 0, -(days - 1))
+}
+
+// countDone counts the check-ins of h from start on.
+func (c *achievementContext) countDone(h Habit, start time.Time) int {
+	from := start.Format("2006-01-02")
+	n := 0
+	for date := range c.logs[h.ID] {
+		if date >= from {
+			n++
+		}
+	}
+	return n
+}
+
+// streak is h's current run of done days, worked out from its check-ins:
+// the stored streak is only updated when the habit is toggled, so it
+// outlives a missed day.
+func (c *achievementContext) streak(h Habit) int {
+	d := c.today
+	if !c.logs[h.ID][d.Format("2006-01-02")] {
+		d = d.AddDate(0, 0, -1) // not done yet today, so count from yesterday
+	}
+	n := 0
+	for c.logs[h.ID][d.Format("2006-01-02")] {
+		n++
+		d = d.AddDate(0, 0, -1)
+	}
+	return n
+}
+
+// perfectDays counts the days from start on on which every habit that had
+// started by then was done.
+func (c *achievementContext) perfectDays(start time.Time) int {
+	if len(c.habits) == 0 {
+		return 0
+	}
+
+	first := c.today.Format("2006-01-02")
+	for _, s := range c.starts {
+		first = min(first, s)
+	}
+	from, err := time.ParseInLocation("2006-01-02", first, time.Local)
+	if err != nil {
+		return 0
+	}
+	if from.Before(start) {
+		from = start
+	}
+
+	n := 0
+	for d := from; !d.After(c.today); d = d.AddDate(0, 0, 1) {
+		date := d.Format("2006-01-02")
+		perfect, started := true, false
+		for _, h := range c.habits {
+			if c.starts[h.ID] > date {
+				continue
+			}
+			started = true
+			if !c.logs[h.ID][date] {
+				perfect = false
+				break
+			}
+		}
+		if perfect && started {
+			n++
+		}
+	}
+	return n
+}
+
+// rate is the percentage of days in the window the habits were done, each
+// counted from its start.
+func (c *achievementContext) rate(habits []Habit, start time.Time) float64 {
+	due, done := 0, 0
+	for _, h := range habits {
+		from, err := time.ParseInLocation("2006-01-02", c.starts[h.ID], time.Local)
+		if err != nil {
+			continue
+		}
+		if from.Before(start) {
+			from = start
+		}
+		for d := from; !d.After(c.today); d = d.AddDate(0, 0, 1) {
+			due++
+			if c.logs[h.ID][d.Format("2006-01-02")] {
+				done++
+			}
+		}
+	}
+	if due == 0 {
+		return 0
+	}
+	return float64(done) * 100 / float64(due)
+}
+
+// value is a metric for one habit, or for all habits when h is nil: totals
+// and XP are summed, streaks, levels and strength take the best habit.
+func (c *achievementContext) value(cond condition, h *Habit) float64 {
+	habits := c.habits
+	if h != nil {
+		habits = []Habit{*h}
+	}
+	start := c.windowStart(cond.Window)
+
+	switch cond.Metric {
+	case "perfect_days":
+		return float64(c.perfectDays(start))
+	case "rate":
+		return c.rate(habits, start)
+	}
+
+	var sum, best float64
+	for _, hb := range habits {
+		var v float64
+		switch cond.Metric {
+		case "streak":
+			v = float64(c.streak(hb))
+		case "total":
+			v = float64(hb.TotalDone)
+			if cond.Window > 0 {
+				v = float64(c.countDone(hb, start))
+			}
+		case "level":
+			v = float64(hb.Level)
+		case "xp":
+			v = float64(hb.XP)
+		case "strength":
+			v = math.Round(hb.Strength)
+		}
+		sum += v
+		best = max(best, v)
+	}
+
+	if cond.Metric == "total" || cond.Metric == "xp" {
+		return sum
+	}
+	return best
+}
+
+// met reports whether every condition of a holds, for h or for all habits
+// when h is nil.
+func (c *achievementContext) met(a achievementDef, h *Habit) bool {
+	for _, cond := range a.conds {
+		v := c.value(cond, h)
+		var ok bool
+		switch cond.Op {
+		case ">=":
+			ok = v >= cond.Value
+		case ">":
+			ok = v > cond.Value
+		case "<=":
+			ok = v <= cond.Value
+		case "<":
+			ok = v < cond.Value
+		case "==":
+			ok = v == cond.Value
+		}
+		if !ok {
+			return false
+		}
+	}
+	return true
+}
+
+// unlock is an achievement earned by a habit, or by all habits together
+// when Habit is the zero Habit.
+type unlock struct {
+	Habit Habit
+	Def   achievementDef
+}
+
+// recordAchievements judges every achievement after a toggle, stores those
+// met for the first time and returns them. Each habit is judged, not only
+// the toggled one, so that rules added to the config unlock for every
+// habit that meets them.
+func recordAchievements(tx *sql.Tx) ([]unlock, error) {
+	ctx, err := loadAchievementContext(tx, time.Now())
+	if err != nil {
+		return nil, err
+	}
+
+	have := make(map[int]map[string]bool)
+	rows, err := tx.Query(`
+		SELECT habit_id, type FROM achievements
+		UNION ALL
+		SELECT 0, type FROM global_achievements
+	`)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	for rows.Next() {
+		var id int
+		var t string
+		if err := rows.Scan(&id, &t); err != nil {
+			return nil, err
+		}
+		if have[id] == nil {
+			have[id] = make(map[string]bool)
+		}
+		have[id][t] = true
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	var unlocked []unlock
+	for _, a := range achievementDefs {
+		if a.Global {
+			if have[0][a.Type] || !ctx.met(a, nil) {
+				continue
+			}
+			if _, err := tx.Exec("INSERT INTO global_achievements (type) VALUES (?)", a.Type); err != nil {
+				return nil, err
+			}
+			unlocked = append(unlocked, unlock{Def: a})
+			continue
+		}
+
+		for _, h := range ctx.habits {
+			if have[h.ID][a.Type] || !ctx.met(a, &h) {
+				continue
+			}
+			if _, err := tx.Exec("INSERT INTO achievements (habit_id, type) VALUES (?, ?)", h.ID, a.Type); err != nil {
+				return nil, err
+			}
+			unlocked = append(unlocked, unlock{Habit: h, Def: a})
+		}
+	}
+
+	return unlocked, nil
+}
+
+// RecordAchievements stores every achievement already met without
+// announcing it, so ones added to the config file, and ones earned before
+// achievements were stored, show up without waiting for a toggle. Only
+// commands that have loaded the config call it, so that a built-in id the
+// config redefines is never judged by the built-in rule.
+func (d *Database) RecordAchievements() error {
+	tx, err := d.db.Begin()
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	if _, err := recordAchievements(tx); err != nil {
+		return fmt.Errorf("failed to record achievements: %w", err)
+	}
+
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("failed to commit transaction: %w", err)
+	}
+	return nil
+}
+
+// Unlocked is a stored achievement with the local time it unlocked.
+type Unlocked struct {
+	Type string
+	At   time.Time
+}
+
+// GetAchievements returns the achievements a habit has unlocked, or those
+// of all habits together when habitID is 0, oldest first.
+func (d *Database) GetAchievements(habitID int) ([]Unlocked, error) {
+	query := "SELECT type, unlocked_at FROM achievements WHERE habit_id = ? ORDER BY unlocked_at, id"
+	args := []any{habitID}
+	if habitID == 0 {
+		query = "SELECT type, unlocked_at FROM global_achievements ORDER BY unlocked_at, type"
+		args = nil
+	}
+
+	rows, err := d.db.Query(query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get achievements: %w", err)
+	}
+	defer rows.Close()
+
+	var unlocked []Unlocked
+	for rows.Next() {
+		var u Unlocked
+		var at string
+		if err := rows.Scan(&u.Type, &at); err != nil {
+			return nil, fmt.Errorf("failed to scan achievement: %w", err)
+		}
+		// Stored by SQLite's CURRENT_TIMESTAMP, in UTC
+		if t, err := time.ParseInLocation("2006-01-02 15:04:05", at, time.UTC); err == nil {
+			u.At = t.Local()
+		}
+		unlocked = append(unlocked, u)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating achievements: %w", err)
+	}
+
+	return unlocked, nil
+}
+
+// achievementLabel is the label of a stored achievement type. Types no
+// longer defined show as they were stored.
+func achievementLabel(achievementType string) string {
+	for _, a := range achievementDefs {
+		if a.Type == achievementType {
+			return a.Label()
+		}
+	}
+	return achievementType
+}
+
+// achievementName is the name of a stored achievement type, without its
+// icon.
+func achievementName(achievementType string) string {
+	for _, a := range achievementDefs {
+		if a.Type == achievementType {
+			return a.Name
+		}
+	}
+	return achievementType
+}
+
+// achievementLabels labels stored achievements with the day each unlocked.
+func achievementLabels(unlocked []Unlocked) []string {
+	labels := make([]string, len(unlocked))
+	for i, u := range unlocked {
+		labels[i] = achievementLabel(u.Type)
+		if !u.At.IsZero() {
+			labels[i] += glyph(" · ", " - ") + u.At.Format("Jan 2, 2006")
+		}
+	}
+	return labels
+}
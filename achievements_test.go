@@ -0,0 +1,203 @@
+package main
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestParseRule(t *testing.T) {
+	tests := []struct {
+		rule    string
+		want    []condition
+		wantErr string
+	}{
+		{rule: "streak >= 7", want: []condition{{Metric: "streak", Op: ">=", Value: 7}}},
+		{rule: "total[30d]>20", want: []condition{{Metric: "total", Window: 30, Op: ">", Value: 20}}},
+		{rule: "Level == 5", want: []condition{{Metric: "level", Op: "==", Value: 5}}},
+		{rule: "rate[90d] >= 80.5", want: []condition{{Metric: "rate", Window: 90, Op: ">=", Value: 80.5}}},
+		{rule: "perfect_days[7d] >= 7", want: []condition{{Metric: "perfect_days", Window: 7, Op: ">=", Value: 7}}},
+		{
+			rule: "total[30d] >= 25 and level >= 3 AND strength < 90",
+			want: []condition{
+				{Metric: "total", Window: 30, Op: ">=", Value: 25},
+				{Metric: "level", Op: ">=", Value: 3},
+				{Metric: "strength", Op: "<", Value: 90},
+			},
+		},
+
+		{rule: "mood >= 3", wantErr: "unknown metric"},
+		{rule: "rate >= 50", wantErr: "needs a time window"},
+		{rule: "streak[7d] >= 3", wantErr: "cannot have a time window"},
+		{rule: "xp[30d] >= 100", wantErr: "cannot have a time window"},
+		{rule: "total[0d] >= 1", wantErr: "at least 1 day"},
+		{rule: "total[30] >= 1", wantErr: "invalid condition"},
+		{rule: "total >= ", wantErr: "invalid condition"},
+		{rule: "total => 5", wantErr: "invalid condition"},
+		{rule: "total >= -1", wantErr: "invalid condition"},
+		{rule: "streak >= 3 and", wantErr: "invalid condition"},
+		{rule: "streak >= 3 or total >= 5", wantErr: "invalid condition"},
+		{rule: "", wantErr: "invalid condition"},
+	}
+
+	for _, tt := range tests {
+		got, err := parseRule(tt.rule)
+		if tt.wantErr != "" {
+			if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+				t.Errorf("parseRule(%q) error = %v, want one containing %q", tt.rule, err, tt.wantErr)
+			}
+			continue
+		}
+		if err != nil {
+			t.Errorf("parseRule(%q): %v", tt.rule, err)
+			continue
+		}
+		if !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("parseRule(%q) = %+v, want %+v", tt.rule, got, tt.want)
+		}
+	}
+}
+
+func TestCompileAchievements(t *testing.T) {
+	tests := []struct {
+		name    string
+		custom  []AchievementConfig
+		wantErr string
+	}{
+		{name: "none"},
+		{name: "new", custom: []AchievementConfig{{ID: "first", Name: "First", Rule: "total >= 1"}}},
+		{name: "replaces built-in", custom: []AchievementConfig{{ID: "streak_3", Name: "Three", Rule: "streak >= 5"}}},
+		{name: "global perfect days", custom: []AchievementConfig{{ID: "p", Name: "P", Rule: "perfect_days >= 7", Global: true}}},
+		{
+			name:    "duplicate id",
+			custom:  []AchievementConfig{{ID: "x", Name: "X", Rule: "total >= 1"}, {ID: "x", Name: "Y", Rule: "total >= 2"}},
+			wantErr: "already defined",
+		},
+		{
+			name:    "perfect days per habit",
+			custom:  []AchievementConfig{{ID: "p", Name: "P", Rule: "total >= 1 and perfect_days >= 7"}},
+			wantErr: "needs \"global\": true",
+		},
+		{name: "missing rule", custom: []AchievementConfig{{ID: "x", Name: "X"}}, wantErr: "required"},
+		{name: "bad rule", custom: []AchievementConfig{{ID: "x", Name: "X", Rule: "fun >= 1"}}, wantErr: "unknown metric"},
+	}
+
+	for _, tt := range tests {
+		all, err := compileAchievements(builtinAchievements, tt.custom)
+		if tt.wantErr != "" {
+			if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+				t.Errorf("%s: error = %v, want one containing %q", tt.name, err, tt.wantErr)
+			}
+			continue
+		}
+		if err != nil {
+			t.Errorf("%s: %v", tt.name, err)
+			continue
+		}
+		for _, a := range all {
+			if len(a.conds) == 0 {
+				t.Errorf("%s: %s has no compiled conditions", tt.name, a.Type)
+			}
+		}
+	}
+
+	all, err := compileAchievements(builtinAchievements, []AchievementConfig{{ID: "streak_3", Name: "Three", Rule: "streak >= 5"}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(all) != len(builtinAchievements) {
+		t.Errorf("replacing a built-in gives %d achievements, want %d", len(all), len(builtinAchievements))
+	}
+	if all[0].Name != "Three" || all[0].Icon != "🏅" || all[0].conds[0].Value != 5 {
+		t.Errorf("replaced built-in = %+v", all[0])
+	}
+}
+
+func TestAchievementRules(t *testing.T) {
+	now := time.Date(2026, 10, 18, 12, 0, 0, 0, time.Local)
+	day := func(offset int) string {
+		return now.AddDate(0, 0, offset).Format("2006-01-02")
+	}
+	// doneDays marks the days from offset from to offset to, inclusive
+	doneDays := func(from, to int) map[string]bool {
+		done := make(map[string]bool)
+		for i := from; i <= to; i++ {
+			done[day(i)] = true
+		}
+		return done
+	}
+
+	// Read: every day of the last 10. Run: the last 4 of 20, at level 3.
+	read := Habit{ID: 1, CurrentStreak: 10, TotalDone: 10, Level: 2, XP: 100, Strength: 55.4}
+	run := Habit{ID: 2, CurrentStreak: 4, TotalDone: 4, Level: 3, XP: 150, Strength: 20}
+	ctx := &achievementContext{
+		habits: []Habit{read, run},
+		logs:   map[int]map[string]bool{1: doneDays(-9, 0), 2: doneDays(-3, 0)},
+		starts: map[int]string{1: day(-9), 2: day(-19)},
+		today:  startOfDay(now),
+	}
+
+	tests := []struct {
+		rule   string
+		global bool
+		habit  Habit
+		want   bool
+	}{
+		{rule: "streak >= 10", habit: read, want: true},
+		{rule: "streak >= 10", habit: run, want: false},
+		{rule: "streak > 9 and level >= 2", habit: read, want: true},
+		{rule: "streak > 9 and level >= 3", habit: read, want: false},
+		{rule: "total[3d] == 3", habit: read, want: true},
+		{rule: "total[3d] >= 4", habit: read, want: false},
+		{rule: "strength >= 55", habit: read, want: true},
+		{rule: "strength > 55", habit: read, want: false},
+		{rule: "rate[10d] == 100", habit: read, want: true},
+		{rule: "rate[20d] >= 50", habit: run, want: false},
+		{rule: "rate[20d] == 20", habit: run, want: true},
+		{rule: "xp < 150", habit: run, want: false},
+
+		// Together: totals and XP are summed, the rest take the best habit
+		{rule: "total >= 14", global: true, want: true},
+		{rule: "total >= 15", global: true, want: false},
+		{rule: "xp == 250", global: true, want: true},
+		{rule: "streak >= 10 and level >= 3", global: true, want: true},
+		{rule: "strength >= 56", global: true, want: false},
+		{rule: "perfect_days == 4", global: true, want: true},
+		{rule: "perfect_days[2d] >= 2", global: true, want: true},
+		{rule: "perfect_days[30d] >= 5", global: true, want: false},
+		{rule: "rate[10d] == 70", global: true, want: true},
+	}
+
+	for _, tt := range tests {
+		conds, err := parseRule(tt.rule)
+		if err != nil {
+			t.Fatalf("parseRule(%q): %v", tt.rule, err)
+		}
+		a := achievementDef{Type: "test", Rule: tt.rule, Global: tt.global, conds: conds}
+
+		var got bool
+		if tt.global {
+			got = ctx.met(a, nil)
+		} else {
+			got = ctx.met(a, &tt.habit)
+		}
+		if got != tt.want {
+			t.Errorf("rule %q (global %v, habit %d) met = %v, want %v", tt.rule, tt.global, tt.habit.ID, got, tt.want)
+		}
+	}
+
+	// The stored streak is only updated on toggles, so one that ended
+	// days ago must not count
+	walk := Habit{ID: 3, CurrentStreak: 12, TotalDone: 12}
+	stale := &achievementContext{
+		habits: []Habit{walk},
+		logs:   map[int]map[string]bool{3: doneDays(-14, -3)},
+		starts: map[int]string{3: day(-14)},
+		today:  startOfDay(now),
+	}
+	conds, _ := parseRule("streak >= 1")
+	if stale.met(achievementDef{Type: "test", conds: conds}, &walk) {
+		t.Error("a streak broken three days ago still meets streak >= 1")
+	}
+}
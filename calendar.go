@@ -0,0 +1,453 @@
+package main
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"github.com/charmbracelet/bubbles/key"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+	"github.com/charmbracelet/x/ansi"
+)
+
+// ============================================================
+// CALENDAR
+// ============================================================
+
+const maxNote = 200
+
+// calendarCellWidth is the width of one day in the month grid.
+const calendarCellWidth = 6
+
+// SetLogNote sets the note on a check-in. An empty note clears it.
+func (d *Database) SetLogNote(habitID int, date, note string) error {
+	note = strings.TrimSpace(note)
+	if utf8.RuneCountInString(note) > maxNote {
+		return fmt.Errorf("note too long (max %d characters)", maxNote)
+	}
+
+	res, err := d.db.Exec("UPDATE logs SET note = ? WHERE habit_id = ? AND date = ?", note, habitID, date)
+	if err != nil {
+		return fmt.Errorf("failed to save note: %w", err)
+	}
+	return requireCheckIn(res.RowsAffected, date)
+}
+
+// SetLogValue records a number against a check-in, such as pages read or
+// minutes run. A nil value clears it.
+func (d *Database) SetLogValue(habitID int, date string, value *float64) error {
+	res, err := d.db.Exec("UPDATE logs SET value = ? WHERE habit_id = ? AND date = ?", value, habitID, date)
+	if err != nil {
+		return fmt.Errorf("failed to save value: %w", err)
+	}
+	return requireCheckIn(res.RowsAffected, date)
+}
+
+func requireCheckIn(rowsAffected func() (int64, error), date string) error {
+	n, err := rowsAffected()
+	if err != nil {
+		return fmt.Errorf("failed to check update: %w", err)
+	}
+	if n == 0 {
+		return fmt.Errorf("no check-in on %s; mark the day done first", date)
+	}
+	return nil
+}
+
+// parseValue reads a log value; empty input clears it.
+func parseValue(s string) (*float64, error) {
+	s = strings.TrimSpace(s)
+	if s == "" {
+		return nil, nil
+	}
+	v, err := strconv.ParseFloat(s, 64)
+	if err != nil {
+		return nil, fmt.Errorf("invalid value %q: expected a number", s)
+	}
+	return &v, nil
+}
+
+func formatValue(v *float64) string {
+	if v == nil {
+		return ""
+	}
+	return strconv.FormatFloat(*v, 'f', -1, 64)
+}
+
+func startOfMonth(t time.Time) time.Time {
+	return time.Date(t.Year(), t.Month(), 1, 0, 0, 0, 0, t.Location())
+}
+
+// addMonths moves n months, keeping the day of the month where it exists
+// and using the last day otherwise (Jan 31 + 1 month is Feb 28).
+func addMonths(t time.Time, n int) time.Time {
+	first := startOfMonth(t).AddDate(0, n, 0)
+	last := first.AddDate(0, 1, -1).Day()
+	return first.AddDate(0, 0, min(t.Day(), last)-1)
+}
+
+func startOfDay(t time.Time) time.Time {
+	return time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, t.Location())
+}
+
+// openCalendar shows the selected habit's current month with today focused.
+func (m *Model) openCalendar() {
+	habit, ok := m.selected()
+	if !ok {
+		m.setMessage("No habit selected to view", "info")
+		return
+	}
+
+	m.calDay = startOfDay(time.Now())
+	if err := m.loadCalendar(habit.ID); err != nil {
+		m.setError(err)
+		return
+	}
+	m.mode = modeCalendar
+}
+
+// loadCalendar loads check-ins from the start of the focused month up to
+// today.
+func (m *Model) loadCalendar(habitID int) error {
+	days := int(time.Since(startOfMonth(m.calDay)).Hours()/24) + 1
+	logs, err := m.db.GetLogsWithTime(habitID, max(days, 0))
+	if err != nil {
+		return err
+	}
+	m.logsWithTime = logs
+	return nil
+}
+
+// focusDay moves the focused day, reloading when it changes month. Days
+// after today cannot be focused.
+func (m *Model) focusDay(day time.Time) {
+	today := startOfDay(time.Now())
+	if day.After(today) {
+		day = today
+	}
+
+	monthChanged := !startOfMonth(day).Equal(startOfMonth(m.calDay))
+	m.calDay = day
+	if !monthChanged {
+		return
+	}
+
+	habit, ok := m.selected()
+	if !ok {
+		return
+	}
+	if err := m.loadCalendar(habit.ID); err != nil {
+		m.setError(err)
+	}
+}
+
+func (m *Model) updateCalendar(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	m.message = ""
+	m.err = nil
+
+	switch {
+	case key.Matches(msg, m.keys.Back, m.keys.Calendar):
+		m.mode = modeList
+
+	case key.Matches(msg, m.keys.Help):
+		m.showHelp()
+
+	case key.Matches(msg, m.keys.PrevDay):
+		m.focusDay(m.calDay.AddDate(0, 0, -1))
+
+	case key.Matches(msg, m.keys.NextDay):
+		m.focusDay(m.calDay.AddDate(0, 0, 1))
+
+	case key.Matches(msg, m.keys.Up):
+		m.focusDay(m.calDay.AddDate(0, 0, -7))
+
+	case key.Matches(msg, m.keys.Down):
+		m.focusDay(m.calDay.AddDate(0, 0, 7))
+
+	case key.Matches(msg, m.keys.PrevMonth):
+		m.focusDay(addMonths(m.calDay, -1))
+
+	case key.Matches(msg, m.keys.NextMonth):
+		m.focusDay(addMonths(m.calDay, 1))
+
+	case key.Matches(msg, m.keys.Today):
+		m.focusDay(startOfDay(time.Now()))
+
+	case key.Matches(msg, m.keys.Toggle):
+		m.toggleCalendarDay()
+
+	case key.Matches(msg, m.keys.Note):
+		m.startAnnotate(modeNote)
+
+	case key.Matches(msg, m.keys.Value):
+		m.startAnnotate(modeValue)
+	}
+
+	return m, nil
+}
+
+// toggleCalendarDay marks the focused day done or not done. Unmarking
+// removes the check-in along with its note and value.
+func (m *Model) toggleCalendarDay() {
+	habit, ok := m.selected()
+	if !ok {
+		return
+	}
+
+	date := m.calDay.Format("2006-01-02")
+	done, err := m.db.ToggleHabit(habit.ID, date)
+	if err != nil {
+		m.setError(err)
+		return
+	}
+	if err := m.refresh(); err != nil {
+		m.setError(err)
+		return
+	}
+	m.selectHabit(habit.ID)
+	if err := m.loadCalendar(habit.ID); err != nil {
+		m.setError(err)
+		return
+	}
+
+	if done {
+		m.setMessage(glyph("✓ ", "")+m.calDay.Format("Jan 2")+" marked as done", "success")
+	} else {
+		m.setMessage(glyph("○ ", "")+m.calDay.Format("Jan 2")+" unmarked", "info")
+	}
+}
+
+// startAnnotate opens the note or value prompt for the focused day, which
+// must already be checked in.
+func (m *Model) startAnnotate(mode mode) {
+	date := m.calDay.Format("2006-01-02")
+	entry, ok := m.logsWithTime[date]
+	if !ok {
+		m.setMessage("Mark "+m.calDay.Format("Jan 2")+" done before adding a note or value", "info")
+		return
+	}
+
+	m.mode = mode
+	if mode == modeNote {
+		m.input.Placeholder = "Note (empty to clear)..."
+		m.input.CharLimit = maxNote
+		m.input.SetValue(entry.Note)
+	} else {
+		m.input.Placeholder = "Value, e.g. 20 (empty to clear)..."
+		m.input.CharLimit = 20
+		m.input.SetValue(formatValue(entry.Value))
+	}
+	m.input.CursorEnd()
+	m.input.Focus()
+}
+
+// updateAnnotate handles the note and value prompts for the focused day.
+func (m *Model) updateAnnotate(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	switch {
+	case key.Matches(msg, m.keys.Cancel):
+		m.mode = modeCalendar
+		m.input.Blur()
+		return m, nil
+
+	case key.Matches(msg, m.keys.Submit):
+		habit, ok := m.selected()
+		if !ok {
+			m.mode = modeList
+			m.input.Blur()
+			return m, nil
+		}
+
+		date := m.calDay.Format("2006-01-02")
+		var err error
+		if m.mode == modeNote {
+			err = m.db.SetLogNote(habit.ID, date, m.input.Value())
+		} else {
+			var value *float64
+			if value, err = parseValue(m.input.Value()); err == nil {
+				err = m.db.SetLogValue(habit.ID, date, value)
+			}
+		}
+
+		if err != nil {
+			m.setError(err)
+			return m, nil
+		}
+
+		if err := m.loadCalendar(habit.ID); err != nil {
+			m.setError(err)
+		} else if m.mode == modeNote {
+			m.setMessage(glyph("✓ ", "")+"Note saved", "success")
+		} else {
+			m.setMessage(glyph("✓ ", "")+"Value saved", "success")
+		}
+
+		m.mode = modeCalendar
+		m.input.Blur()
+		return m, nil
+	}
+
+	var cmd tea.Cmd
+	m.input, cmd = m.input.Update(msg)
+	return m, cmd
+}
+
+func (m *Model) viewAnnotate() string {
+	var s strings.Builder
+
+	title := "Day Note"
+	if m.mode == modeValue {
+		title = "Day Value"
+	}
+	s.WriteString(titleStyle.Render(title) + "\n\n")
+
+	habit, _ := m.selected()
+	s.WriteString(normalStyle.Render(habit.Name+" · "+m.calDay.Format("Monday, January 2, 2006")) + "\n\n")
+	s.WriteString(m.input.View() + "\n\n")
+	s.WriteString(m.renderHelp())
+
+	return s.String()
+}
+
+// viewCalendar shows one month of the selected habit, a week per row, with
+// the focused day's check-in details below.
+func (m *Model) viewCalendar() string {
+	habit, ok := m.selected()
+	if !ok {
+		return ""
+	}
+
+	var s strings.Builder
+
+	title := fmt.Sprintf("%s%s · %s", glyph("📅 ", ""), habit.Name, m.calDay.Format("January 2006"))
+	s.WriteString(titleStyle.Render(title) + "\n\n")
+
+	// Weekday header, weekends picked out as in the heatmap
+	for i, day := range []string{"Sun", "Mon", "Tue", "Wed", "Thu", "Fri", "Sat"} {
+		color := theme.Label
+		if i == 0 || i == 6 {
+			color = theme.Weekend
+		}
+		s.WriteString(lipgloss.NewStyle().Foreground(color).Bold(true).Width(calendarCellWidth).Render(" " + day))
+	}
+	s.WriteString("\n")
+
+	today := startOfDay(time.Now())
+	first := startOfMonth(m.calDay)
+	daysInMonth := first.AddDate(0, 1, -1).Day()
+	done, elapsed := 0, 0
+
+	y := strings.Count(s.String(), "\n")
+	col := int(first.Weekday())
+	s.WriteString(strings.Repeat(" ", col*calendarCellWidth))
+
+	for d := 1; d <= daysInMonth; d++ {
+		date := first.AddDate(0, 0, d-1)
+		dateStr := date.Format("2006-01-02")
+		entry, checkedIn := m.logsWithTime[dateStr]
+
+		mark := " "
+		markStyle := dimStyle
+		switch {
+		case checkedIn:
+			mark = glyph("✓", "x")
+			markStyle = successStyle
+		case !date.After(today):
+			mark = glyph("·", ".")
+		}
+		if !date.After(today) {
+			elapsed++
+			if checkedIn {
+				done++
+			}
+		}
+
+		annotated := " "
+		if entry.Note != "" || entry.Value != nil {
+			annotated = "*"
+		}
+
+		dayStyle := normalStyle
+		if date.Equal(today) {
+			dayStyle = lipgloss.NewStyle().Foreground(theme.Accent).Bold(true)
+		}
+
+		// Brackets as well as the background, so focus shows without color
+		edge, noteStyle := lipgloss.NewStyle(), warningStyle
+		left, right := " ", " "
+		if date.Equal(m.calDay) {
+			left, right = "[", "]"
+			edge = edge.Foreground(theme.Accent).Background(theme.Selection)
+			dayStyle = dayStyle.Background(theme.Selection)
+			markStyle = markStyle.Background(theme.Selection)
+			noteStyle = noteStyle.Background(theme.Selection)
+		}
+		s.WriteString(edge.Render(left) + dayStyle.Render(fmt.Sprintf("%2d", d)) +
+			markStyle.Render(mark) + noteStyle.Render(annotated) + edge.Render(right))
+
+		if !date.After(today) {
+			m.addHit(hitZone{y: y, x0: col * calendarCellWidth, x1: (col + 1) * calendarCellWidth, row: -1, date: dateStr})
+		}
+
+		col++
+		if col == 7 && d < daysInMonth {
+			s.WriteString("\n")
+			col = 0
+			y++
+		}
+	}
+	s.WriteString("\n\n")
+
+	legend := fmt.Sprintf("%s done   %s missed   * note or value   [ ] focused", glyph("✓", "x"), glyph("·", "."))
+	s.WriteString(dimStyle.Render(legend) + "\n")
+	if elapsed > 0 {
+		s.WriteString(dimStyle.Render(fmt.Sprintf("Done %d of %d days this month (%d%%)", done, elapsed, done*100/elapsed)) + "\n")
+	}
+	s.WriteString("\n")
+
+	s.WriteString(m.viewDayDetail() + "\n\n")
+	s.WriteString(m.renderHelp())
+
+	return s.String()
+}
+
+// viewDayDetail describes the focused day's check-in.
+func (m *Model) viewDayDetail() string {
+	var s strings.Builder
+
+	s.WriteString(subtitleStyle.Render(m.calDay.Format("Monday, January 2, 2006")) + "\n")
+
+	label := func(text string) string {
+		return lipgloss.NewStyle().Foreground(theme.Label).Width(12).Render(text)
+	}
+
+	entry, ok := m.logsWithTime[m.calDay.Format("2006-01-02")]
+	if !ok {
+		s.WriteString(label("Status:") + dimStyle.Render("Not done"))
+		return s.String()
+	}
+
+	s.WriteString(label("Status:") + successStyle.Render("Done") + "\n")
+	if at, err := time.Parse("2006-01-02 15:04:05", entry.Timestamp); err == nil {
+		s.WriteString(label("Checked in:") + normalStyle.Render(at.Format("Jan 2, 3:04 PM")) + "\n")
+	}
+	value := dimStyle.Render("none")
+	if entry.Value != nil {
+		value = normalStyle.Render(formatValue(entry.Value))
+	}
+	s.WriteString(label("Value:") + value + "\n")
+
+	note := dimStyle.Render("none")
+	if entry.Note != "" {
+		text := entry.Note
+		if width := m.contentWidth(); width > 12 {
+			text = ansi.Wrap(text, width-12, "")
+		}
+		note = lipgloss.NewStyle().Foreground(theme.Text).Render(text)
+	}
+	s.WriteString(lipgloss.JoinHorizontal(lipgloss.Top, label("Note:"), note))
+
+	return s.String()
+}
@@ -0,0 +1,30 @@
+package main
+
+import (
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestSetLogNoteCountsCharacters(t *testing.T) {
+	db := newTestDatabase(t)
+	if err := db.AddHabit("Read"); err != nil {
+		t.Fatal(err)
+	}
+	habits, err := db.GetHabits()
+	if err != nil {
+		t.Fatal(err)
+	}
+	id, today := habits[0].ID, time.Now().Format("2006-01-02")
+	if _, err := db.ToggleHabit(id, today); err != nil {
+		t.Fatal(err)
+	}
+
+	// Two bytes per character, so the byte count is twice the limit
+	if err := db.SetLogNote(id, today, strings.Repeat("é", maxNote)); err != nil {
+		t.Errorf("note of %d characters: %v", maxNote, err)
+	}
+	if err := db.SetLogNote(id, today, strings.Repeat("é", maxNote+1)); err == nil {
+		t.Errorf("note of %d characters was accepted", maxNote+1)
+	}
+}
@@ -0,0 +1,293 @@
+package main
+
+import (
+	"database/sql"
+	"flag"
+	"fmt"
+	"regexp"
+	"sort"
+	"strings"
+)
+
+// ============================================================
+// CATEGORIES AND TAGS
+// ============================================================
+
+const (
+	maxCategoryName      = 30
+	maxTagLength         = 30
+	defaultCategoryColor = "#7D56F4"
+	defaultCategoryIcon  = "📁"
+)
+
+type Category struct {
+	ID    int
+	Name  string
+	Color string
+	Icon  string
+}
+
+// defaultCategories are created with a fresh database.
+var defaultCategories = []Category{
+	{Name: "Health", Color: "#39D353", Icon: "💪"},
+	{Name: "Work", Color: "#4EA8DE", Icon: "💼"},
+	{Name: "Learning", Color: "#FFA500", Icon: "📚"},
+}
+
+var hexColorPattern = regexp.MustCompile(`^#[0-9A-Fa-f]{6}$`)
+
+// seedCategories creates the default categories once per database, so
+// deleting all of them does not bring them back on the next start.
+func seedCategories(db *sql.DB) error {
+	var seeded int
+	if err := db.QueryRow("SELECT COUNT(*) FROM settings WHERE key = 'categories_seeded'").Scan(&seeded); err != nil {
+		return err
+	}
+	if seeded > 0 {
+		return nil
+	}
+
+	tx, err := db.Begin()
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	var count int
+	if err := tx.QueryRow("SELECT COUNT(*) FROM categories").Scan(&count); err != nil {
+		return err
+	}
+	if count == 0 {
+		for _, c := range defaultCategories {
+			if _, err := tx.Exec("INSERT INTO categories (name, color, icon) VALUES (?, ?, ?)", c.Name, c.Color, c.Icon); err != nil {
+				return err
+			}
+		}
+	}
+	if _, err := tx.Exec("INSERT INTO settings (key, value) VALUES ('categories_seeded', '1')"); err != nil {
+		return err
+	}
+
+	return tx.Commit()
+}
+
+func (d *Database) GetCategories() ([]Category, error) {
+	rows, err := d.db.Query("SELECT id, name, color, icon FROM categories ORDER BY name COLLATE NOCASE")
+	if err != nil {
+		return nil, fmt.Errorf("failed to get categories: %w", err)
+	}
+	defer rows.Close()
+
+	var categories []Category
+	for rows.Next() {
+		var c Category
+		if err := rows.Scan(&c.ID, &c.Name, &c.Color, &c.Icon); err != nil {
+			return nil, fmt.Errorf("failed to scan category: %w", err)
+		}
+		categories = append(categories, c)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating categories: %w", err)
+	}
+
+	return categories, nil
+}
+
+// SaveCategory creates the named category or updates its color and icon.
+// Empty color or icon values leave the current ones unchanged.
+func (d *Database) SaveCategory(name, color, icon string) error {
+	name = strings.TrimSpace(name)
+	if name == "" {
+		return fmt.Errorf("category name cannot be empty")
+	}
+	if len(name) > maxCategoryName {
+		return fmt.Errorf("category name too long (max %d characters)", maxCategoryName)
+	}
+	if color != "" && !hexColorPattern.MatchString(color) {
+		return fmt.Errorf("invalid color %q (expected #RRGGBB)", color)
+	}
+
+	_, err := d.db.Exec(`
+		INSERT INTO categories (name, color, icon)
+		VALUES (?, COALESCE(NULLIF(?, ''), ?), COALESCE(NULLIF(?, ''), ?))
+		ON CONFLICT(name) DO UPDATE SET
+			color = COALESCE(NULLIF(?, ''), color),
+			icon = COALESCE(NULLIF(?, ''), icon)
+	`, name, color, defaultCategoryColor, icon, defaultCategoryIcon, color, icon)
+	if err != nil {
+		return fmt.Errorf("failed to save category: %w", err)
+	}
+
+	return nil
+}
+
+func (d *Database) DeleteCategory(name string) error {
+	tx, err := d.db.Begin()
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	var id int
+	err = tx.QueryRow("SELECT id FROM categories WHERE name = ?", strings.TrimSpace(name)).Scan(&id)
+	if err == sql.ErrNoRows {
+		return fmt.Errorf("category not found")
+	}
+	if err != nil {
+		return fmt.Errorf("failed to find category: %w", err)
+	}
+
+	if _, err := tx.Exec("UPDATE habits SET category_id = NULL WHERE category_id = ?", id); err != nil {
+		return fmt.Errorf("failed to uncategorize habits: %w", err)
+	}
+	if _, err := tx.Exec("DELETE FROM categories WHERE id = ?", id); err != nil {
+		return fmt.Errorf("failed to delete category: %w", err)
+	}
+
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("failed to commit transaction: %w", err)
+	}
+	return nil
+}
+
+// SetHabitCategory moves a habit into the named category, creating it if
+// needed. An empty name removes the habit from its category.
+func (d *Database) SetHabitCategory(habitID int, name string) error {
+	name = strings.TrimSpace(name)
+	if name == "" {
+		if _, err := d.db.Exec("UPDATE habits SET category_id = NULL WHERE id = ?", habitID); err != nil {
+			return fmt.Errorf("failed to clear category: %w", err)
+		}
+		return nil
+	}
+
+	var id int
+	err := d.db.QueryRow("SELECT id FROM categories WHERE name = ?", name).Scan(&id)
+	if err == sql.ErrNoRows {
+		if err := d.SaveCategory(name, "", ""); err != nil {
+			return err
+		}
+		err = d.db.QueryRow("SELECT id FROM categories WHERE name = ?", name).Scan(&id)
+	}
+	if err != nil {
+		return fmt.Errorf("failed to find category: %w", err)
+	}
+
+	if _, err := d.db.Exec("UPDATE habits SET category_id = ? WHERE id = ?", id, habitID); err != nil {
+		return fmt.Errorf("failed to set category: %w", err)
+	}
+	return nil
+}
+
+// parseTags splits a comma or space separated list, dropping leading '#'
+// characters, duplicates and empty entries.
+func parseTags(s string) ([]string, error) {
+	seen := make(map[string]bool)
+	var tags []string
+	for _, field := range strings.FieldsFunc(s, func(r rune) bool { return r == ',' || r == ' ' }) {
+		tag := strings.ToLower(strings.TrimLeft(field, "#"))
+		if tag == "" || seen[tag] {
+			continue
+		}
+		if len(tag) > maxTagLength {
+			return nil, fmt.Errorf("tag %q too long (max %d characters)", tag, maxTagLength)
+		}
+		seen[tag] = true
+		tags = append(tags, tag)
+	}
+	sort.Strings(tags)
+	return tags, nil
+}
+
+func (d *Database) SetHabitTags(habitID int, tags []string) error {
+	tx, err := d.db.Begin()
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	if _, err := tx.Exec("DELETE FROM habit_tags WHERE habit_id = ?", habitID); err != nil {
+		return fmt.Errorf("failed to clear tags: %w", err)
+	}
+	for _, tag := range tags {
+		if _, err := tx.Exec("INSERT INTO habit_tags (habit_id, tag) VALUES (?, ?)", habitID, tag); err != nil {
+			return fmt.Errorf("failed to add tag: %w", err)
+		}
+	}
+
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("failed to commit transaction: %w", err)
+	}
+	return nil
+}
+
+func (d *Database) getTags() (map[int][]string, error) {
+	rows, err := d.db.Query("SELECT habit_id, tag FROM habit_tags ORDER BY tag")
+	if err != nil {
+		return nil, fmt.Errorf("failed to get tags: %w", err)
+	}
+	defer rows.Close()
+
+	tags := make(map[int][]string)
+	for rows.Next() {
+		var id int
+		var tag string
+		if err := rows.Scan(&id, &tag); err != nil {
+			return nil, fmt.Errorf("failed to scan tag: %w", err)
+		}
+		tags[id] = append(tags[id], tag)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating tags: %w", err)
+	}
+
+	return tags, nil
+}
+
+// runCategory implements `habit category list|set|delete`.
+func runCategory(args []string) error {
+	if len(args) == 0 {
+		return fmt.Errorf("usage: habit category list|set|delete")
+	}
+
+	db, err := NewDatabase()
+	if err != nil {
+		return err
+	}
+	defer db.Close()
+
+	switch args[0] {
+	case "list":
+		categories, err := db.GetCategories()
+		if err != nil {
+			return err
+		}
+		for _, c := range categories {
+			fmt.Printf("%s %-20s %s\n", c.Icon, c.Name, c.Color)
+		}
+		return nil
+
+	case "set":
+		fset := flag.NewFlagSet("category set", flag.ContinueOnError)
+		color := fset.String("color", "", "category color (#RRGGBB)")
+		icon := fset.String("icon", "", "category icon")
+		if len(args) < 2 {
+			return fmt.Errorf("usage: habit category set <name> [--color #RRGGBB] [--icon ICON]")
+		}
+		if err := fset.Parse(args[2:]); err != nil {
+			return err
+		}
+		return db.SaveCategory(args[1], *color, *icon)
+
+	case "delete":
+		if len(args) != 2 {
+			return fmt.Errorf("usage: habit category delete <name>")
+		}
+		return db.DeleteCategory(args[1])
+
+	default:
+		return fmt.Errorf("unknown category command %q", args[0])
+	}
+}
@@ -0,0 +1,34 @@
+package main
+
+import "testing"
+
+func TestDeletedDefaultCategoriesStayDeleted(t *testing.T) {
+	db := newTestDatabase(t)
+
+	categories, err := db.GetCategories()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(categories) != len(defaultCategories) {
+		t.Fatalf("fresh database has %d categories, want %d", len(categories), len(defaultCategories))
+	}
+	for _, c := range categories {
+		if err := db.DeleteCategory(c.Name); err != nil {
+			t.Fatal(err)
+		}
+	}
+	db.Close()
+
+	db, err = NewDatabase()
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer db.Close()
+	categories, err = db.GetCategories()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(categories) != 0 {
+		t.Errorf("reopened database has %d categories, want none", len(categories))
+	}
+}
@@ -0,0 +1,371 @@
+package main
+
+import (
+	"fmt"
+	"math"
+	"strings"
+	"time"
+
+	"github.com/charmbracelet/bubbles/key"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+)
+
+// ============================================================
+// CHARTS
+// ============================================================
+
+// sparklineWeeks is how many weeks the list sparklines cover.
+const sparklineWeeks = 8
+
+// chartHeight is the number of rows in a chart's plot area.
+const chartHeight = 10
+
+// chartAxisWidth is the width of the y-axis labels and tick.
+const chartAxisWidth = 7
+
+type chartKind int
+
+const (
+	chartWeekly chartKind = iota
+	chartMonthly
+	chartRolling
+	chartXP
+	chartStrength
+)
+
+var chartNames = []string{"Weekly completion rate", "Monthly completion rate", "Rolling 30-day average", "XP over time", "Habit strength"}
+
+// GetLogDates returns every day a habit was done, oldest first.
+func (d *Database) GetLogDates(habitID int) ([]string, error) {
+	rows, err := d.db.Query("SELECT date FROM logs WHERE habit_id = ? ORDER BY date", habitID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get logs: %w", err)
+	}
+	defer rows.Close()
+
+	var dates []string
+	for rows.Next() {
+		var date string
+		if err := rows.Scan(&date); err != nil {
+			return nil, fmt.Errorf("failed to scan log: %w", err)
+		}
+		dates = append(dates, date)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating logs: %w", err)
+	}
+
+	return dates, nil
+}
+
+// completionRate is the share of days from start to end (inclusive) found
+// in done, as a percentage.
+func completionRate(done map[string]bool, start, end time.Time) float64 {
+	days, hits := 0, 0
+	for d := start; !d.After(end); d = d.AddDate(0, 0, 1) {
+		days++
+		if done[d.Format("2006-01-02")] {
+			hits++
+		}
+	}
+	if days == 0 {
+		return 0
+	}
+	return float64(hits) * 100 / float64(days)
+}
+
+// weeklyRates returns the completion rate of each of the last n seven-day
+// windows ending today, oldest first.
+func weeklyRates(done map[string]bool, n int, now time.Time) []float64 {
+	today := startOfDay(now)
+	rates := make([]float64, n)
+	for i := range rates {
+		end := today.AddDate(0, 0, -7*(n-1-i))
+		rates[i] = completionRate(done, end.AddDate(0, 0, -6), end)
+	}
+	return rates
+}
+
+// monthlyRates returns the completion rate of each of the last n calendar
+// months, oldest first. The current month counts only the days so far.
+func monthlyRates(done map[string]bool, n int, now time.Time) []float64 {
+	today := startOfDay(now)
+	rates := make([]float64, n)
+	for i := range rates {
+		start := startOfMonth(today).AddDate(0, -(n - 1 - i), 0)
+		end := start.AddDate(0, 1, -1)
+		if end.After(today) {
+			end = today
+		}
+		rates[i] = completionRate(done, start, end)
+	}
+	return rates
+}
+
+// rollingRates returns, for each of the last n days, the completion rate
+// over the 30 days ending that day.
+func rollingRates(done map[string]bool, n int, now time.Time) []float64 {
+	today := startOfDay(now)
+	rates := make([]float64, n)
+	for i := range rates {
+		end := today.AddDate(0, 0, -(n - 1 - i))
+		rates[i] = completionRate(done, end.AddDate(0, 0, -29), end)
+	}
+	return rates
+}
+
+// xpHistory replays computeStats at the end of each of the last n days to
+// give the XP the habit had then. dates must be sorted oldest first.
+func xpHistory(dates []string, n int, now time.Time) []float64 {
+	today := startOfDay(now)
+	xp := make([]float64, n)
+	upTo := 0
+	for i := range xp {
+		day := today.AddDate(0, 0, -(n - 1 - i))
+		dayStr := day.Format("2006-01-02")
+		for upTo < len(dates) && dates[upTo] <= dayStr {
+			upTo++
+		}
+
+		// computeStats wants the newest date first
+		before := make([]string, upTo)
+		for j := range before {
+			before[j] = dates[upTo-1-j]
+		}
+		if stats, err := computeStats(before, day); err == nil {
+			xp[i] = float64(stats.XP)
+		}
+	}
+	return xp
+}
+
+// sparkline draws values between 0 and top as one block character each.
+func sparkline(values []float64, top float64) string {
+	levels := []rune(glyph("▁▂▃▄▅▆▇█", "_.:-=+*#"))
+	var b strings.Builder
+	for _, v := range values {
+		i := 0
+		if top > 0 {
+			i = int(math.Round(v / top * float64(len(levels)-1)))
+		}
+		b.WriteRune(levels[min(max(i, 0), len(levels)-1)])
+	}
+	return b.String()
+}
+
+// habitSparkline is the list's weekly completion trend for a habit.
+func (m *Model) habitSparkline(habitID int) string {
+	return sparkline(weeklyRates(m.recentLogs[habitID], sparklineWeeks, time.Now()), 100)
+}
+
+// loadRecentLogs loads the check-ins the list sparklines need, for all
+// habits in one query.
+func (m *Model) loadRecentLogs() error {
+	start := time.Now().AddDate(0, 0, -7*sparklineWeeks).Format("2006-01-02")
+	logs, err := m.db.GetLogsSince(start)
+	if err != nil {
+		return err
+	}
+	m.recentLogs = logs
+	return nil
+}
+
+// openCharts shows the charts for the selected habit.
+func (m *Model) openCharts() {
+	habit, ok := m.selected()
+	if !ok {
+		m.setMessage("No habit selected to view", "info")
+		return
+	}
+
+	dates, err := m.db.GetLogDates(habit.ID)
+	if err != nil {
+		m.setError(err)
+		return
+	}
+	m.chartDates = dates
+	m.mode = modeChart
+}
+
+func (m *Model) updateChart(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	switch {
+	case key.Matches(msg, m.keys.Back, m.keys.Charts):
+		m.mode = modeList
+
+	case key.Matches(msg, m.keys.Help):
+		m.showHelp()
+
+	case key.Matches(msg, m.keys.PrevChart):
+		m.chart = (m.chart + chartKind(len(chartNames)) - 1) % chartKind(len(chartNames))
+
+	case key.Matches(msg, m.keys.NextChart):
+		m.chart = (m.chart + 1) % chartKind(len(chartNames))
+	}
+	return m, nil
+}
+
+// plotChart draws values as bars (each barWidth wide, a column apart) that
+// reach the top at maxY, or as a line of points with maxY on the top row.
+// label formats the y-axis values.
+func plotChart(values []float64, maxY float64, bars bool, barWidth int, label func(float64) string) []string {
+	if maxY <= 0 {
+		maxY = 1
+	}
+
+	eighths := []rune(glyph(" ▁▂▃▄▅▆▇█", " ...::::#"))
+	point := glyph("•", "*")
+	barStyle := lipgloss.NewStyle().Foreground(theme.Success)
+	pointStyle := lipgloss.NewStyle().Foreground(theme.Accent).Bold(true)
+	axisStyle := lipgloss.NewStyle().Foreground(theme.Label)
+
+	var lines []string
+	for row := chartHeight - 1; row >= 0; row-- {
+		axis := strings.Repeat(" ", chartAxisWidth-2) + glyph("┤ ", "| ")
+		// Lines only label their ends, as their middle row is no round value
+		if row == chartHeight-1 || row == 0 || (bars && row == chartHeight/2) {
+			value := maxY * float64(row) / float64(chartHeight-1)
+			if bars {
+				value = maxY * float64(row+1) / chartHeight
+			}
+			axis = fmt.Sprintf("%*s", chartAxisWidth-2, label(value)) + glyph("┤ ", "| ")
+		}
+
+		var b strings.Builder
+		for _, v := range values {
+			if bars {
+				// Height of the bar in eighths of a row
+				fill := int(math.Round(v/maxY*chartHeight*8)) - row*8
+				var cell string
+				switch {
+				case fill >= 8:
+					cell = string(eighths[8])
+				case fill > 0:
+					cell = string(eighths[fill])
+				default:
+					cell = " "
+				}
+				b.WriteString(barStyle.Render(strings.Repeat(cell, barWidth)) + " ")
+			} else if int(math.Round(v/maxY*(chartHeight-1))) == row {
+				b.WriteString(pointStyle.Render(point))
+			} else {
+				b.WriteString(" ")
+			}
+		}
+		lines = append(lines, axisStyle.Render(axis)+b.String())
+	}
+
+	return lines
+}
+
+// viewChart shows one chart for the selected habit, picked with the
+// arrow keys.
+func (m *Model) viewChart() string {
+	habit, ok := m.selected()
+	if !ok {
+		return ""
+	}
+
+	var s strings.Builder
+
+	s.WriteString(titleStyle.Render(glyph("📉 ", "")+habit.Name) + "\n\n")
+
+	s.WriteString(subtitleStyle.Render(chartNames[m.chart]) +
+		dimStyle.Render(fmt.Sprintf("  (%d of %d)", m.chart+1, len(chartNames))) + "\n\n")
+
+	now := time.Now()
+	done := make(map[string]bool, len(m.chartDates))
+	for _, d := range m.chartDates {
+		done[d] = true
+	}
+
+	// Room for the plot after the y-axis
+	room := 90
+	if width := m.contentWidth(); width > 0 {
+		room = max(width-chartAxisWidth, 10)
+	}
+
+	percent := func(v float64) string { return fmt.Sprintf("%.0f%%", v) }
+
+	var values []float64
+	var lines []string
+	var first, last, caption string
+	columns := 0
+
+	switch m.chart {
+	case chartWeekly:
+		n := min(26, room/3)
+		values = weeklyRates(done, n, now)
+		lines = plotChart(values, 100, true, 2, percent)
+		first = now.AddDate(0, 0, -7*n+1).Format("Jan 2")
+		last = "this week"
+		caption = fmt.Sprintf("Last 7 days: %.0f%%%saverage over %d weeks: %.0f%%", values[n-1], glyph(" · ", " | "), n, mean(values))
+		columns = n * 3
+
+	case chartMonthly:
+		n := min(12, room/3)
+		values = monthlyRates(done, n, now)
+		lines = plotChart(values, 100, true, 2, percent)
+		first = startOfMonth(now).AddDate(0, -(n - 1), 0).Format("Jan 2006")
+		last = "this month"
+		caption = fmt.Sprintf("This month so far: %.0f%%%saverage over %d months: %.0f%%", values[n-1], glyph(" · ", " | "), n, mean(values))
+		columns = n * 3
+
+	case chartRolling:
+		n := min(90, room)
+		values = rollingRates(done, n, now)
+		lines = plotChart(values, 100, false, 1, percent)
+		first = now.AddDate(0, 0, -(n - 1)).Format("Jan 2")
+		last = "today"
+		caption = fmt.Sprintf("30-day average today: %.0f%% (%+.0f points over %d days)", values[n-1], values[n-1]-values[0], n)
+		columns = n
+
+	case chartXP:
+		n := min(90, room)
+		values = xpHistory(m.chartDates, n, now)
+		top := 100.0
+		for _, v := range values {
+			top = math.Max(top, v)
+		}
+		top = math.Ceil(top/100) * 100
+		lines = plotChart(values, top, false, 1, func(v float64) string { return fmt.Sprintf("%.0f", v) })
+		first = now.AddDate(0, 0, -(n - 1)).Format("Jan 2")
+		last = "today"
+		caption = fmt.Sprintf("%d XP today, %+.0f over %d days", habit.XP, values[n-1]-values[0], n)
+		columns = n
+
+	case chartStrength:
+		n := min(90, room)
+		values = strengthHistory(m.chartDates, n, now)
+		lines = plotChart(values, 100, false, 1, percent)
+		first = now.AddDate(0, 0, -(n - 1)).Format("Jan 2")
+		last = "today"
+		caption = fmt.Sprintf("Strength today: %.0f%% (%s)", values[n-1], describeTrend(habit.StrengthTrend))
+		columns = n
+	}
+
+	s.WriteString(strings.Join(lines, "\n") + "\n")
+
+	// X axis with the first and last dates under its ends
+	axisStyle := lipgloss.NewStyle().Foreground(theme.Label)
+	s.WriteString(axisStyle.Render(strings.Repeat(" ", chartAxisWidth-2)+glyph("└", "+")+strings.Repeat(glyph("─", "-"), columns+1)) + "\n")
+	gap := max(columns-len(first)-len(last), 1)
+	s.WriteString(axisStyle.Render(strings.Repeat(" ", chartAxisWidth)+first+strings.Repeat(" ", gap)+last) + "\n\n")
+
+	s.WriteString(normalStyle.Render(caption) + "\n\n")
+	s.WriteString(m.renderHelp())
+
+	return s.String()
+}
+
+func mean(values []float64) float64 {
+	if len(values) == 0 {
+		return 0
+	}
+	sum := 0.0
+	for _, v := range values {
+		sum += v
+	}
+	return sum / float64(len(values))
+}
@@ -0,0 +1,248 @@
+package main
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/charmbracelet/lipgloss"
+)
+
+// ============================================================
+// COMBINED HEATMAP
+// ============================================================
+
+// GetDailyTotals returns how many habits were checked in on each day from
+// start on. Logs left behind by deleted habits are not counted.
+func (d *Database) GetDailyTotals(start string) (map[string]int, error) {
+	rows, err := d.db.Query(`
+		SELECT l.date, COUNT(*)
+		FROM logs l
+		JOIN habits h ON h.id = l.habit_id
+		WHERE l.date >= ?
+		GROUP BY l.date
+	`, start)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get daily totals: %w", err)
+	}
+	defer rows.Close()
+
+	totals := make(map[string]int)
+	for rows.Next() {
+		var date string
+		var count int
+		if err := rows.Scan(&date, &count); err != nil {
+			return nil, fmt.Errorf("failed to scan daily total: %w", err)
+		}
+		totals[date] = count
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating daily totals: %w", err)
+	}
+
+	return totals, nil
+}
+
+// GetHabitStarts returns the first day each habit is expected to be done:
+// the day it was created, or its earliest check-in when that was
+// backfilled before then.
+func (d *Database) GetHabitStarts() (map[int]string, error) {
+	rows, err := d.db.Query(`
+		SELECT h.id, MIN(substr(h.created_at, 1, 10), COALESCE(MIN(l.date), substr(h.created_at, 1, 10)))
+		FROM habits h
+		LEFT JOIN logs l ON l.habit_id = h.id
+		GROUP BY h.id
+	`)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get habit start dates: %w", err)
+	}
+	defer rows.Close()
+
+	starts := make(map[int]string)
+	for rows.Next() {
+		var id int
+		var start string
+		if err := rows.Scan(&id, &start); err != nil {
+			return nil, fmt.Errorf("failed to scan habit start date: %w", err)
+		}
+		starts[id] = start
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating habit start dates: %w", err)
+	}
+
+	return starts, nil
+}
+
+// intensityLevel maps the share of scheduled habits done on a day to a
+// heatmap level: 0 for none, then quarters (up to 25%, 50%, 75%, above).
+func intensityLevel(done, scheduled int) int {
+	if done == 0 || scheduled == 0 {
+		return 0
+	}
+	return min((done*4+scheduled-1)/scheduled, 4)
+}
+
+// intensitySymbol is the cell for a level. Each level has its own shape so
+// the combined heatmap reads without color.
+func intensitySymbol(level int) string {
+	fancy := []string{"░░", "▒░", "▒▒", "▓▓", "██"}
+	plain := []string{"..", "+.", "++", "#+", "##"}
+	return glyph(fancy[level], plain[level])
+}
+
+func intensityColor(level int) lipgloss.Color {
+	return []lipgloss.Color{colorNone, colorLevel1, colorLevel2, colorLevel3, colorLevel4}[level]
+}
+
+// openCombinedHeatmap switches the heatmap to all habits.
+func (m *Model) openCombinedHeatmap() {
+	if err := m.loadCombinedHeatmap(); err != nil {
+		m.setError(err)
+		return
+	}
+	m.heatmapAll = true
+	m.inspected = ""
+	m.mode = modeHeatmap
+}
+
+// loadCombinedHeatmap loads daily totals for the longest heatmap range.
+func (m *Model) loadCombinedHeatmap() error {
+	start := time.Now().AddDate(0, 0, -(maxWeeks+1)*7).Format("2006-01-02")
+	totals, err := m.db.GetDailyTotals(start)
+	if err != nil {
+		return err
+	}
+	starts, err := m.db.GetHabitStarts()
+	if err != nil {
+		return err
+	}
+
+	achievements, err := m.db.GetAchievements(0)
+	if err != nil {
+		return err
+	}
+
+	m.dayTotals = totals
+	m.habitStarts = starts
+	m.achievements = achievements
+	return nil
+}
+
+// dayCompletion returns the habits done and scheduled on date. A habit is
+// scheduled every day from its start date, as habits have no schedule of
+// their own.
+func (m *Model) dayCompletion(date string) (done, scheduled int) {
+	for _, start := range m.habitStarts {
+		if start <= date {
+			scheduled++
+		}
+	}
+	return min(m.dayTotals[date], scheduled), scheduled
+}
+
+// describeDay shows how many habits were done on date.
+func (m *Model) describeDay(date string) {
+	day, err := time.Parse("2006-01-02", date)
+	if err != nil {
+		return
+	}
+
+	done, scheduled := m.dayCompletion(date)
+	if scheduled == 0 {
+		m.setMessage(day.Format("Mon, Jan 2 2006")+": no habits yet", "info")
+		return
+	}
+	m.setMessage(fmt.Sprintf("%s: %d of %d habits done (%d%%)",
+		day.Format("Mon, Jan 2 2006"), done, scheduled, done*100/scheduled), "info")
+}
+
+// viewCombinedHeatmap is the heatmap for all habits together, shaded by the
+// share of habits done each day.
+func (m *Model) viewCombinedHeatmap() string {
+	var s strings.Builder
+
+	headerBox := lipgloss.NewStyle().
+		Bold(true).
+		Foreground(theme.Accent).
+		Border(roundedBorder()).
+		BorderForeground(theme.Accent).
+		Padding(0, 2).
+		MarginBottom(1)
+
+	headerContent := fmt.Sprintf("%sAll habits  %s", glyph("📊 ", ""),
+		dimStyle.Render(plural(len(m.habitStarts), "habit")))
+	s.WriteString(headerBox.Render(headerContent) + "\n\n")
+
+	endDate := time.Now()
+	weeks := m.heatmapWeeks()
+	startDate, _, numWeeks := heatmapWindow(weeks, endDate)
+
+	grid := m.renderHeatmapGrid(strings.Count(s.String(), "\n"), startDate, endDate, numWeeks, func(date string) (string, lipgloss.Color) {
+		level := intensityLevel(m.dayCompletion(date))
+		return intensitySymbol(level), intensityColor(level)
+	})
+	s.WriteString(grid + "\n\n")
+
+	// Totals over the days shown
+	var daysShown, activeDays, perfectDays, doneSum, scheduledSum int
+	for d := startDate; !d.After(endDate); d = d.AddDate(0, 0, 1) {
+		done, scheduled := m.dayCompletion(d.Format("2006-01-02"))
+		if scheduled == 0 {
+			continue
+		}
+		daysShown++
+		doneSum += done
+		scheduledSum += scheduled
+		if done > 0 {
+			activeDays++
+		}
+		if done == scheduled {
+			perfectDays++
+		}
+	}
+	average := 0.0
+	if scheduledSum > 0 {
+		average = float64(doneSum) * 100 / float64(scheduledSum)
+	}
+
+	statRow := func(label, value string, color lipgloss.Color) string {
+		return lipgloss.NewStyle().Foreground(theme.Label).Width(20).Render(label) +
+			lipgloss.NewStyle().Foreground(color).Bold(true).Render(value)
+	}
+
+	var stats strings.Builder
+	stats.WriteString(subtitleStyle.Render(glyph("📈 ", "")+"Statistics") + "\n\n")
+	stats.WriteString(statRow("Average Completion:", fmt.Sprintf("%.1f%%", average), theme.Accent) + "\n")
+	stats.WriteString(statRow("Check-ins:", fmt.Sprintf("%d of %d", doneSum, scheduledSum), theme.Success) + "\n")
+	stats.WriteString(statRow("Active Days:", fmt.Sprintf("%d of %d", activeDays, daysShown), theme.Warning) + "\n")
+	stats.WriteString(statRow("Perfect Days:", fmt.Sprintf("%d", perfectDays), theme.Highlight))
+	if len(m.achievements) > 0 {
+		stats.WriteString("\n\n" + subtitleStyle.Render(glyph("🏆 ", "")+"Achievements"))
+		for _, ach := range achievementLabels(m.achievements) {
+			stats.WriteString("\n  " + successStyle.Render(ach))
+		}
+	}
+	s.WriteString(m.panelStyle().Render(stats.String()) + "\n\n")
+
+	// Legend with the threshold of each level
+	le := glyph("≤", "<=")
+	thresholds := []string{"none", le + "25%", le + "50%", le + "75%", ">75%"}
+	var legend strings.Builder
+	legend.WriteString("Habits done:")
+	for level, label := range thresholds {
+		legend.WriteString("  " + lipgloss.NewStyle().Foreground(intensityColor(level)).Render(intensitySymbol(level)) + " " + label)
+	}
+
+	showing := fmt.Sprintf("Showing %d weeks", weeks)
+	if weeks < m.weeks {
+		showing = fmt.Sprintf("Showing %d of %d weeks (window too narrow)", weeks, m.weeks)
+	}
+	s.WriteString(lipgloss.NewStyle().Foreground(theme.Dim).Padding(0, 1).Render(legend.String()+"\n"+showing) + "\n\n")
+
+	s.WriteString(m.renderHelp())
+
+	return s.String()
+}
@@ -0,0 +1,103 @@
+package main
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+)
+
+// ============================================================
+// CONFIG
+// ============================================================
+
+// Config is read from ~/.config/habit-tracker/config.json. Every field is
+// optional; a missing file yields the zero Config.
+type Config struct {
+	Webhooks     []WebhookConfig     `json:"webhooks"`
+	HookTimeout  int                 `json:"hook_timeout_seconds"` // defaults to defaultHookTimeout
+	Reminders    ReminderConfig      `json:"reminders"`
+	Theme        string              `json:"theme"`        // built-in or user theme name; defaults to "default"
+	ASCII        bool                `json:"ascii"`        // plain ASCII instead of emoji and box drawing
+	NoMouse      bool                `json:"no_mouse"`     // leave the mouse to the terminal for text selection
+	Keys         map[string][]string `json:"keys"`         // action name -> keys; an empty list unbinds the action
+	Achievements []AchievementConfig `json:"achievements"` // added to the built-in ones
+}
+
+type ReminderConfig struct {
+	Command     []string `json:"command"`      // title and body are appended; defaults to notify-send
+	QuietHours  string   `json:"quiet_hours"`  // e.g. "22:30-07:00"
+	StreakAlert string   `json:"streak_alert"` // time after which unfinished streaks alert; defaults to 21:00
+	Snooze      int      `json:"snooze_minutes"`
+}
+
+// AchievementConfig defines an achievement; see parseRule for the rule
+// language. An ID of a built-in achievement replaces it.
+type AchievementConfig struct {
+	ID     string `json:"id"`
+	Name   string `json:"name"`
+	Icon   string `json:"icon"`   // defaults to a medal
+	Rule   string `json:"rule"`   // e.g. "streak >= 7" or "total[30d] >= 20 and level >= 3"
+	Global bool   `json:"global"` // judge all habits together instead of each habit
+}
+
+type WebhookConfig struct {
+	URL         string   `json:"url"`
+	Secret      string   `json:"secret"`       // HMAC-SHA256 key for X-Habit-Signature
+	Events      []string `json:"events"`       // empty means all events
+	MaxAttempts int      `json:"max_attempts"` // defaults to defaultWebhookAttempts
+}
+
+func configDir() (string, error) {
+	dir, err := os.UserConfigDir()
+	if err != nil {
+		return "", fmt.Errorf("failed to locate config directory: %w", err)
+	}
+	return filepath.Join(dir, "habit-tracker"), nil
+}
+
+func loadConfig() (Config, error) {
+	var cfg Config
+
+	dir, err := configDir()
+	if err != nil {
+		return cfg, err
+	}
+
+	data, err := os.ReadFile(filepath.Join(dir, "config.json"))
+	if errors.Is(err, fs.ErrNotExist) {
+		return cfg, nil
+	}
+	if err != nil {
+		return cfg, fmt.Errorf("failed to read config: %w", err)
+	}
+
+	if err := json.Unmarshal(data, &cfg); err != nil {
+		return cfg, fmt.Errorf("invalid config.json: %w", err)
+	}
+
+	for i, hook := range cfg.Webhooks {
+		if hook.URL == "" {
+			return cfg, fmt.Errorf("webhook %d: url is required", i+1)
+		}
+	}
+
+	if cfg.Reminders.QuietHours != "" {
+		if _, _, err := parseClockRange(cfg.Reminders.QuietHours); err != nil {
+			return cfg, fmt.Errorf("reminders.quiet_hours: %w", err)
+		}
+	}
+	if cfg.Reminders.StreakAlert != "" {
+		if _, err := parseClock(cfg.Reminders.StreakAlert); err != nil {
+			return cfg, fmt.Errorf("reminders.streak_alert: %w", err)
+		}
+	}
+
+	if err := setAchievements(cfg.Achievements); err != nil {
+		return cfg, fmt.Errorf("achievements: %w", err)
+	}
+
+	return cfg, nil
+}
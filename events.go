@@ -0,0 +1,127 @@
+package main
+
+import "time"
+
+// ============================================================
+// EVENTS
+// ============================================================
+
+const (
+	EventHabitCompleted      = "habit.completed"
+	EventStreakMilestone     = "streak.milestone"
+	EventAchievementUnlocked = "achievement.unlocked"
+	EventLevelUp             = "level.up"
+	EventGoalMet             = "goal.met"
+	EventPing                = "ping"
+)
+
+var streakMilestones = []int{3, 7, 30, 100, 365}
+
+// Event describes something that happened to a habit. Events are emitted
+// after the transaction that caused them has committed.
+type Event struct {
+	Type        string    `json:"type"`
+	HabitID     int       `json:"habit_id"`
+	HabitName   string    `json:"habit_name"`
+	Date        string    `json:"date,omitempty"`
+	Streak      int       `json:"streak"`
+	TotalDone   int       `json:"total_done"`
+	Level       int       `json:"level"`
+	XP          int       `json:"xp"`
+	Milestone   int       `json:"milestone,omitempty"`
+	Achievement string    `json:"achievement,omitempty"`
+	Goal        string    `json:"goal,omitempty"`
+	Time        time.Time `json:"time"`
+}
+
+// Subscribe registers fn to be called for every event the database emits.
+// Listeners run synchronously and should hand slow work off to a goroutine.
+func (d *Database) Subscribe(fn func(Event)) {
+	d.listeners = append(d.listeners, fn)
+}
+
+func (d *Database) emit(events []Event) {
+	for _, e := range events {
+		for _, fn := range d.listeners {
+			fn(e)
+		}
+	}
+}
+
+func newEvent(eventType string, h Habit) Event {
+	return Event{
+		Type:      eventType,
+		HabitID:   h.ID,
+		HabitName: h.Name,
+		Streak:    h.CurrentStreak,
+		TotalDone: h.TotalDone,
+		Level:     h.Level,
+		XP:        h.XP,
+		Time:      time.Now(),
+	}
+}
+
+// habitEvents compares a habit before and after a toggle of date.
+func habitEvents(before, after Habit, date string, isDone bool) []Event {
+	var events []Event
+
+	if isDone {
+		e := newEvent(EventHabitCompleted, after)
+		e.Date = date
+		events = append(events, e)
+	}
+
+	for _, milestone := range streakMilestones {
+		if before.CurrentStreak < milestone && after.CurrentStreak >= milestone {
+			e := newEvent(EventStreakMilestone, after)
+			e.Date = date
+			e.Milestone = milestone
+			events = append(events, e)
+		}
+	}
+
+	if after.Level > before.Level {
+		events = append(events, newEvent(EventLevelUp, after))
+	}
+
+	return events
+}
+
+// achievementEvents announces the achievements a toggle unlocked. Those
+// earned by all habits together have no habit.
+func achievementEvents(unlocked []unlock) []Event {
+	var events []Event
+	for _, u := range unlocked {
+		e := newEvent(EventAchievementUnlocked, u.Habit)
+		e.Achievement = u.Def.Type
+		events = append(events, e)
+	}
+	return events
+}
+
+// goalEvents announces the goals a toggle met.
+func goalEvents(h Habit, met []Goal) []Event {
+	var events []Event
+	for _, g := range met {
+		e := newEvent(EventGoalMet, h)
+		e.Date = g.MetAt
+		e.Goal = g.String()
+		events = append(events, e)
+	}
+	return events
+}
+
+// attachIntegrations subscribes the configured outgoing integrations to db
+// and returns a function that flushes them on shutdown.
+func attachIntegrations(db *Database, cfg Config) func() {
+	webhooks := NewWebhookDispatcher(db, cfg.Webhooks)
+	db.Subscribe(webhooks.Dispatch)
+
+	hooks := NewHookRunner(cfg)
+	db.Subscribe(hooks.Run)
+
+	return func() {
+		webhooks.Close()
+		hooks.Wait()
+	}
+}
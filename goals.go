@@ -0,0 +1,674 @@
+package main
+
+import (
+	"database/sql"
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/charmbracelet/bubbles/key"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+)
+
+// ============================================================
+// GOALS
+// ============================================================
+
+const (
+	goalTotal  = "total"  // check-ins from the start date
+	goalStreak = "streak" // a current streak of target days
+	goalRate   = "rate"   // target percent of the days from start to deadline
+)
+
+// goalPaceDays is how far back a habit's pace is measured for projections.
+const goalPaceDays = 28
+
+const maxGoalInput = 60
+
+type Goal struct {
+	ID       int
+	HabitID  int
+	Kind     string
+	Target   int
+	Start    string // first day counted
+	Deadline string // empty when open-ended
+	MetAt    string // empty until met
+}
+
+type goalStatus int
+
+const (
+	goalOnTrack goalStatus = iota
+	goalAtRisk
+	goalMissed
+	goalMet
+)
+
+type goalProgress struct {
+	Current   int
+	Needed    int
+	Status    goalStatus
+	Projected time.Time // zero when there is no pace to project from
+}
+
+// parseGoalDate reads "2006-01-02" or a month and day with an optional
+// year ("Dec 31", "December 31 2026"). Without a year the next such day
+// from now is meant.
+func parseGoalDate(s string, now time.Time) (time.Time, error) {
+	s = strings.TrimSpace(strings.ReplaceAll(s, ",", " "))
+	s = strings.Join(strings.Fields(s), " ")
+
+	if t, err := time.ParseInLocation("2006-01-02", s, time.Local); err == nil {
+		return t, nil
+	}
+	for _, layout := range []string{"Jan 2 2006", "January 2 2006"} {
+		if t, err := time.ParseInLocation(layout, s, time.Local); err == nil {
+			return t, nil
+		}
+	}
+	for _, layout := range []string{"Jan 2", "January 2"} {
+		t, err := time.ParseInLocation(layout, s, time.Local)
+		if err != nil {
+			continue
+		}
+		t = t.AddDate(now.Year()-t.Year(), 0, 0)
+		if t.Before(startOfDay(now)) {
+			t = t.AddDate(1, 0, 0)
+		}
+		return t, nil
+	}
+
+	return time.Time{}, fmt.Errorf("invalid date %q (try 2006-01-02 or Dec 31)", s)
+}
+
+// parseGoal reads a goal typed by the user:
+//
+//	50 [check-ins] [by Dec 31]   check-ins from today on
+//	30-day streak [by Dec 31]    a current streak of 30 days
+//	80% this month               80% of this month's days
+//	80% by Dec 31                80% of the days from today to Dec 31
+func parseGoal(s string, now time.Time) (Goal, error) {
+	text := strings.ToLower(strings.Join(strings.Fields(s), " "))
+	today := startOfDay(now)
+	g := Goal{Start: today.Format("2006-01-02")}
+
+	if head, date, ok := strings.Cut(text, " by "); ok {
+		deadline, err := parseGoalDate(date, now)
+		if err != nil {
+			return g, err
+		}
+		if deadline.Before(today) {
+			return g, fmt.Errorf("deadline %s has already passed", deadline.Format("Jan 2, 2006"))
+		}
+		g.Deadline = deadline.Format("2006-01-02")
+		text = head
+	}
+
+	if head, ok := strings.CutSuffix(text, " this month"); ok {
+		if g.Deadline != "" {
+			return g, fmt.Errorf("use either \"this month\" or a deadline")
+		}
+		g.Start = startOfMonth(today).Format("2006-01-02")
+		g.Deadline = startOfMonth(today).AddDate(0, 1, -1).Format("2006-01-02")
+		text = head
+		if !strings.HasSuffix(text, "%") {
+			return g, fmt.Errorf("\"this month\" goals are percentages, e.g. 80%% this month")
+		}
+	}
+
+	number := func(s string) (int, error) {
+		n, err := strconv.Atoi(strings.TrimSpace(s))
+		if err != nil || n <= 0 {
+			return 0, fmt.Errorf("invalid goal %q (try \"50 by Dec 31\", \"30-day streak\" or \"80%% this month\")", strings.TrimSpace(s))
+		}
+		return n, nil
+	}
+
+	var err error
+	switch {
+	case strings.HasSuffix(text, "%"):
+		g.Kind = goalRate
+		if g.Target, err = number(strings.TrimSuffix(text, "%")); err != nil {
+			return g, err
+		}
+		if g.Target > 100 {
+			return g, fmt.Errorf("a completion rate can be at most 100%%")
+		}
+		if g.Deadline == "" {
+			return g, fmt.Errorf("percentage goals need a period: \"this month\" or \"by <date>\"")
+		}
+
+	case strings.Contains(text, "streak"):
+		g.Kind = goalStreak
+		n := strings.NewReplacer("streak", "", "-day", "", " days", "", " day", "").Replace(text)
+		if g.Target, err = number(n); err != nil {
+			return g, err
+		}
+
+	default:
+		g.Kind = goalTotal
+		n := strings.NewReplacer("check-ins", "", "check-in", "", "checkins", "", "completions", "", "completion", "", "times", "", "time", "").Replace(text)
+		if g.Target, err = number(n); err != nil {
+			return g, err
+		}
+	}
+
+	return g, nil
+}
+
+// formatGoalDate shows the year only when it is not this one.
+func formatGoalDate(date string) string {
+	t, err := time.Parse("2006-01-02", date)
+	if err != nil {
+		return date
+	}
+	if t.Year() == time.Now().Year() {
+		return t.Format("Jan 2")
+	}
+	return t.Format("Jan 2, 2006")
+}
+
+// String describes the goal the way it can be typed in.
+func (g Goal) String() string {
+	var s string
+	switch g.Kind {
+	case goalStreak:
+		s = fmt.Sprintf("%d-day streak", g.Target)
+	case goalRate:
+		return fmt.Sprintf("%d%% of days, %s to %s", g.Target, formatGoalDate(g.Start), formatGoalDate(g.Deadline))
+	default:
+		s = plural(g.Target, "check-in")
+	}
+	if g.Deadline != "" {
+		s += " by " + formatGoalDate(g.Deadline)
+	}
+	return s
+}
+
+// AddGoal saves a goal for a habit.
+func (d *Database) AddGoal(g Goal) error {
+	var deadline any
+	if g.Deadline != "" {
+		deadline = g.Deadline
+	}
+	_, err := d.db.Exec("INSERT INTO goals (habit_id, kind, target, start, deadline) VALUES (?, ?, ?, ?, ?)",
+		g.HabitID, g.Kind, g.Target, g.Start, deadline)
+	if err != nil {
+		return fmt.Errorf("failed to add goal: %w", err)
+	}
+	return nil
+}
+
+func (d *Database) DeleteGoal(id int) error {
+	if _, err := d.db.Exec("DELETE FROM goals WHERE id = ?", id); err != nil {
+		return fmt.Errorf("failed to delete goal: %w", err)
+	}
+	return nil
+}
+
+// GetGoals returns a habit's goals, or every goal when habitID is 0,
+// soonest deadline first. Goals left behind by deleted habits are skipped.
+func (d *Database) GetGoals(habitID int) ([]Goal, error) {
+	return queryGoals(d.db, habitID)
+}
+
+type goalQuerier interface {
+	Query(query string, args ...any) (*sql.Rows, error)
+}
+
+func queryGoals(q goalQuerier, habitID int) ([]Goal, error) {
+	rows, err := q.Query(`
+		SELECT g.id, g.habit_id, g.kind, g.target, g.start, COALESCE(g.deadline, ''), COALESCE(g.met_at, '')
+		FROM goals g
+		JOIN habits h ON h.id = g.habit_id
+		WHERE ? = 0 OR g.habit_id = ?
+		ORDER BY g.deadline IS NULL, g.deadline, g.id
+	`, habitID, habitID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get goals: %w", err)
+	}
+	defer rows.Close()
+
+	var goals []Goal
+	for rows.Next() {
+		var g Goal
+		if err := rows.Scan(&g.ID, &g.HabitID, &g.Kind, &g.Target, &g.Start, &g.Deadline, &g.MetAt); err != nil {
+			return nil, fmt.Errorf("failed to scan goal: %w", err)
+		}
+		goals = append(goals, g)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating goals: %w", err)
+	}
+
+	return goals, nil
+}
+
+// markGoalAlerted records that an at-risk notification went out on date.
+func (d *Database) markGoalAlerted(id int, date string) error {
+	_, err := d.db.Exec("UPDATE goals SET alerted = ? WHERE id = ?", date, id)
+	return err
+}
+
+// getGoalAlerts returns the goals already warned about on date.
+func (d *Database) getGoalAlerts(date string) (map[int]bool, error) {
+	rows, err := d.db.Query("SELECT id FROM goals WHERE alerted = ?", date)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get goal alerts: %w", err)
+	}
+	defer rows.Close()
+
+	alerted := make(map[int]bool)
+	for rows.Next() {
+		var id int
+		if err := rows.Scan(&id); err != nil {
+			return nil, fmt.Errorf("failed to scan goal alert: %w", err)
+		}
+		alerted[id] = true
+	}
+
+	return alerted, rows.Err()
+}
+
+// recordGoals marks the habit's goals it has met for the first time and
+// returns them. It runs in the toggle's transaction, after the stats are
+// recalculated.
+func recordGoals(tx *sql.Tx, habit Habit) ([]Goal, error) {
+	goals, err := queryGoals(tx, habit.ID)
+	if err != nil {
+		return nil, err
+	}
+
+	rows, err := tx.Query("SELECT date FROM logs WHERE habit_id = ?", habit.ID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	done := make(map[string]bool)
+	for rows.Next() {
+		var date string
+		if err := rows.Scan(&date); err != nil {
+			return nil, err
+		}
+		done[date] = true
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	now := time.Now()
+	var met []Goal
+	for _, g := range goals {
+		if g.MetAt != "" || goalProgressOf(g, habit, done, now).Status != goalMet {
+			continue
+		}
+		g.MetAt = now.Format("2006-01-02")
+		if _, err := tx.Exec("UPDATE goals SET met_at = ? WHERE id = ?", g.MetAt, g.ID); err != nil {
+			return nil, err
+		}
+		met = append(met, g)
+	}
+
+	return met, nil
+}
+
+// goalProgressOf works out how far along a goal is, given every day the
+// habit was done. Projections assume the habit keeps the pace of its last
+// four weeks.
+func goalProgressOf(g Goal, habit Habit, done map[string]bool, now time.Time) goalProgress {
+	today := startOfDay(now)
+	todayStr := today.Format("2006-01-02")
+
+	var deadline time.Time
+	if g.Deadline != "" {
+		deadline, _ = time.ParseInLocation("2006-01-02", g.Deadline, time.Local)
+	}
+	start, _ := time.ParseInLocation("2006-01-02", g.Start, time.Local)
+
+	// The next day that can still be done
+	next := today
+	if done[todayStr] {
+		next = today.AddDate(0, 0, 1)
+	}
+
+	countDone := func(from, to time.Time) int {
+		n := 0
+		for d := from; !d.After(to); d = d.AddDate(0, 0, 1) {
+			if done[d.Format("2006-01-02")] {
+				n++
+			}
+		}
+		return n
+	}
+	pace := float64(countDone(today.AddDate(0, 0, -(goalPaceDays-1)), today)) / goalPaceDays
+
+	// project is the day remaining more check-ins are reached at pace
+	project := func(remaining int, pace float64) time.Time {
+		if pace <= 0 {
+			return time.Time{}
+		}
+		return next.AddDate(0, 0, int(math.Ceil(float64(remaining)/pace))-1)
+	}
+
+	var p goalProgress
+	switch g.Kind {
+	case goalStreak:
+		p.Current, p.Needed = habit.CurrentStreak, g.Target
+		// Done every day from here on
+		p.Projected = next.AddDate(0, 0, max(g.Target-habit.CurrentStreak, 1)-1)
+		if !deadline.IsZero() && deadline.Before(today) {
+			// Only the streak as it stood on the deadline counts
+			p.Current = 0
+			for d := deadline; done[d.Format("2006-01-02")]; d = d.AddDate(0, 0, -1) {
+				p.Current++
+			}
+		}
+
+	case goalRate:
+		last := deadline
+		days := int(last.Sub(start).Hours()/24+0.5) + 1
+		p.Needed = int(math.Ceil(float64(g.Target) * float64(days) / 100))
+		p.Current = countDone(start, last)
+
+		left := 0
+		if !next.After(last) {
+			left = int(last.Sub(next).Hours()/24+0.5) + 1
+		}
+		if p.Current+left < p.Needed && p.Current < p.Needed {
+			p.Status = goalMissed
+		}
+		if elapsed := int(next.Sub(start).Hours()/24 + 0.5); elapsed >= 7 {
+			pace = float64(p.Current) / float64(elapsed)
+		}
+		p.Projected = project(p.Needed-p.Current, pace)
+
+	default:
+		p.Needed = g.Target
+		last := today
+		if !deadline.IsZero() && deadline.Before(today) {
+			last = deadline
+		}
+		p.Current = countDone(start, last)
+		p.Projected = project(p.Needed-p.Current, pace)
+	}
+
+	switch {
+	case g.MetAt != "" || p.Current >= p.Needed:
+		p.Status = goalMet
+		p.Projected = time.Time{}
+	case p.Status == goalMissed, !deadline.IsZero() && deadline.Before(today):
+		p.Status = goalMissed
+	case !deadline.IsZero() && (p.Projected.IsZero() || p.Projected.After(deadline)):
+		p.Status = goalAtRisk
+	}
+
+	return p
+}
+
+// goalsAtRisk returns every goal at risk of being missed, with its habit.
+func (d *Database) goalsAtRisk(now time.Time) ([]Goal, map[int]Habit, error) {
+	goals, err := d.GetGoals(0)
+	if err != nil || len(goals) == 0 {
+		return nil, nil, err
+	}
+
+	habits, err := d.GetHabits()
+	if err != nil {
+		return nil, nil, err
+	}
+	byID := make(map[int]Habit, len(habits))
+	for _, h := range habits {
+		byID[h.ID] = h
+	}
+
+	logs, err := d.GetLogsSince("")
+	if err != nil {
+		return nil, nil, err
+	}
+
+	var risky []Goal
+	for _, g := range goals {
+		if goalProgressOf(g, byID[g.HabitID], logs[g.HabitID], now).Status == goalAtRisk {
+			risky = append(risky, g)
+		}
+	}
+	return risky, byID, nil
+}
+
+// warnGoalsAtRisk puts goals at risk in the message line, for the list on
+// startup.
+func (m *Model) warnGoalsAtRisk() error {
+	risky, habits, err := m.db.goalsAtRisk(time.Now())
+	if err != nil || len(risky) == 0 {
+		return err
+	}
+
+	g := risky[0]
+	msg := fmt.Sprintf("%sGoal at risk: %s, %s", glyph("⚠ ", ""), habits[g.HabitID].Name, g)
+	if len(risky) > 1 {
+		msg += fmt.Sprintf(" (and %d more)", len(risky)-1)
+	}
+	m.setMessage(msg, "info")
+	return nil
+}
+
+// noteGoalsMet is subscribed to the database so goals met by any toggle
+// can be announced once the toggle's own message is set.
+func (m *Model) noteGoalsMet(e Event) {
+	if e.Type == EventGoalMet {
+		m.goalsMet = append(m.goalsMet, e.HabitName+": "+e.Goal)
+	}
+}
+
+// announceGoals replaces the message with any goals just met.
+func (m *Model) announceGoals() {
+	if len(m.goalsMet) == 0 {
+		return
+	}
+	m.setMessage(glyph("🎯 ", "")+"Goal met! "+strings.Join(m.goalsMet, "; "), "success")
+	m.goalsMet = nil
+}
+
+// openGoals shows the goals of the selected habit.
+func (m *Model) openGoals() {
+	if _, ok := m.selected(); !ok {
+		m.setMessage("No habit selected to view", "info")
+		return
+	}
+	if err := m.loadGoals(); err != nil {
+		m.setError(err)
+		return
+	}
+	m.goalCursor = 0
+	m.mode = modeGoals
+}
+
+func (m *Model) loadGoals() error {
+	habit, _ := m.selected()
+	goals, err := m.db.GetGoals(habit.ID)
+	if err != nil {
+		return err
+	}
+	dates, err := m.db.GetLogDates(habit.ID)
+	if err != nil {
+		return err
+	}
+
+	m.goals = goals
+	m.goalDates = make(map[string]bool, len(dates))
+	for _, d := range dates {
+		m.goalDates[d] = true
+	}
+	m.goalCursor = min(m.goalCursor, max(len(goals)-1, 0))
+	return nil
+}
+
+func (m *Model) updateGoals(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	m.message = ""
+	m.err = nil
+
+	switch {
+	case key.Matches(msg, m.keys.Back, m.keys.Goals):
+		m.mode = modeList
+
+	case key.Matches(msg, m.keys.Help):
+		m.showHelp()
+
+	case key.Matches(msg, m.keys.Up):
+		m.goalCursor = max(m.goalCursor-1, 0)
+
+	case key.Matches(msg, m.keys.Down):
+		m.goalCursor = min(m.goalCursor+1, max(len(m.goals)-1, 0))
+
+	case key.Matches(msg, m.keys.Add):
+		m.mode = modeGoalAdd
+		m.input.Placeholder = "e.g. 50 by Dec 31, 30-day streak, 80% this month"
+		m.input.CharLimit = maxGoalInput
+		m.input.SetValue("")
+		m.input.Focus()
+
+	case key.Matches(msg, m.keys.Delete):
+		if len(m.goals) == 0 {
+			return m, nil
+		}
+		g := m.goals[m.goalCursor]
+		if err := m.db.DeleteGoal(g.ID); err != nil {
+			m.setError(err)
+			return m, nil
+		}
+		if err := m.loadGoals(); err != nil {
+			m.setError(err)
+			return m, nil
+		}
+		m.setMessage("Goal removed: "+g.String(), "info")
+	}
+	return m, nil
+}
+
+func (m *Model) updateGoalAdd(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	switch {
+	case key.Matches(msg, m.keys.Cancel):
+		m.mode = modeGoals
+		m.message = ""
+		m.err = nil
+		m.input.Blur()
+		return m, nil
+
+	case key.Matches(msg, m.keys.Submit):
+		habit, ok := m.selected()
+		if !ok {
+			m.mode = modeList
+			m.input.Blur()
+			return m, nil
+		}
+
+		g, err := parseGoal(m.input.Value(), time.Now())
+		if err != nil {
+			m.setError(err)
+			return m, nil
+		}
+		g.HabitID = habit.ID
+		if err := m.db.AddGoal(g); err != nil {
+			m.setError(err)
+			return m, nil
+		}
+		if err := m.loadGoals(); err != nil {
+			m.setError(err)
+		} else {
+			m.setMessage(glyph("✓ ", "")+"Goal added: "+g.String(), "success")
+		}
+
+		m.mode = modeGoals
+		m.input.Blur()
+		return m, nil
+	}
+
+	var cmd tea.Cmd
+	m.input, cmd = m.input.Update(msg)
+	return m, cmd
+}
+
+func (m *Model) viewGoalAdd() string {
+	var s strings.Builder
+
+	habit, _ := m.selected()
+	s.WriteString(titleStyle.Render("New Goal") + "\n\n")
+	s.WriteString(normalStyle.Render(habit.Name) + "\n\n")
+	s.WriteString(m.input.View() + "\n\n")
+	s.WriteString(dimStyle.Render("50 by Dec 31      50 check-ins from today, by Dec 31\n"+
+		"30-day streak     a current streak of 30 days (add \"by <date>\" for a deadline)\n"+
+		"80% this month    done on 80% of this month's days\n"+
+		"80% by Dec 31     done on 80% of the days from today to Dec 31") + "\n\n")
+	s.WriteString(m.renderHelp())
+
+	return s.String()
+}
+
+// viewGoals lists the selected habit's goals with their progress and
+// projected completion.
+func (m *Model) viewGoals() string {
+	habit, ok := m.selected()
+	if !ok {
+		return ""
+	}
+
+	var s strings.Builder
+	s.WriteString(titleStyle.Render(glyph("🎯 ", "")+habit.Name+" Goals") + "\n\n")
+
+	if len(m.goals) == 0 {
+		s.WriteString(dimStyle.Render("No goals yet. Press "+m.keys.Add.Help().Key+" to set one.") + "\n\n")
+	}
+
+	now := time.Now()
+	for i, g := range m.goals {
+		p := goalProgressOf(g, habit, m.goalDates, now)
+
+		marker, nameStyle := "  ", normalStyle
+		if i == m.goalCursor {
+			marker, nameStyle = glyph("› ", "> "), lipgloss.NewStyle().Foreground(theme.Accent).Bold(true)
+		}
+
+		var status string
+		switch p.Status {
+		case goalMet:
+			met := "met"
+			if g.MetAt != "" {
+				met += " " + formatGoalDate(g.MetAt)
+			}
+			status = successStyle.Render(glyph("✓ ", "") + met)
+		case goalMissed:
+			status = errorStyle.Render(glyph("✗ ", "") + "missed")
+		case goalAtRisk:
+			status = warningStyle.Render(glyph("⚠ ", "") + "at risk")
+		default:
+			status = dimStyle.Render("on track")
+		}
+
+		s.WriteString(nameStyle.Render(marker+g.String()) + "  " + status + "\n")
+		s.WriteString("    " + m.getProgressBar(min(p.Current, p.Needed), p.Needed, 20) +
+			normalStyle.Render(fmt.Sprintf(" %d/%d", p.Current, p.Needed)) + "\n")
+
+		var detail string
+		switch {
+		case p.Status == goalMet || p.Status == goalMissed:
+		case p.Projected.IsZero():
+			detail = "No check-ins in the last four weeks to project from"
+		case g.Kind == goalStreak:
+			detail = "Reached " + formatGoalDate(p.Projected.Format("2006-01-02")) + " if done every day"
+		default:
+			detail = "At the current pace, reached " + formatGoalDate(p.Projected.Format("2006-01-02"))
+		}
+		if detail != "" {
+			s.WriteString(dimStyle.Render("    "+detail) + "\n")
+		}
+		s.WriteString("\n")
+	}
+
+	s.WriteString(m.renderHelp())
+
+	return s.String()
+}
@@ -0,0 +1,151 @@
+package main
+
+import (
+	"testing"
+	"time"
+)
+
+func TestParseGoal(t *testing.T) {
+	now := time.Date(2026, 10, 18, 12, 0, 0, 0, time.Local)
+
+	tests := []struct {
+		input   string
+		want    Goal
+		wantErr bool
+	}{
+		{input: "50", want: Goal{Kind: goalTotal, Target: 50, Start: "2026-10-18"}},
+		{input: "50 check-ins by Dec 31", want: Goal{Kind: goalTotal, Target: 50, Start: "2026-10-18", Deadline: "2026-12-31"}},
+		{input: "1 time by 2027-01-15", want: Goal{Kind: goalTotal, Target: 1, Start: "2026-10-18", Deadline: "2027-01-15"}},
+		{input: "30-day streak", want: Goal{Kind: goalStreak, Target: 30, Start: "2026-10-18"}},
+		{input: "7 day streak by Nov 1", want: Goal{Kind: goalStreak, Target: 7, Start: "2026-10-18", Deadline: "2026-11-01"}},
+		{input: "80% this month", want: Goal{Kind: goalRate, Target: 80, Start: "2026-10-01", Deadline: "2026-10-31"}},
+		{input: "  90%   by  December 31  ", want: Goal{Kind: goalRate, Target: 90, Start: "2026-10-18", Deadline: "2026-12-31"}},
+		// A month and day already past this year means next year's
+		{input: "10 by Jan 5", want: Goal{Kind: goalTotal, Target: 10, Start: "2026-10-18", Deadline: "2027-01-05"}},
+
+		{input: "80%", wantErr: true},
+		{input: "150% this month", wantErr: true},
+		{input: "50 this month", wantErr: true},
+		{input: "80% this month by Dec 31", wantErr: true},
+		{input: "50 by Jan 5 2020", wantErr: true},
+		{input: "50 by someday", wantErr: true},
+		{input: "0", wantErr: true},
+		{input: "lots", wantErr: true},
+		{input: "streak", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		got, err := parseGoal(tt.input, now)
+		if tt.wantErr {
+			if err == nil {
+				t.Errorf("parseGoal(%q) = %+v, want an error", tt.input, got)
+			}
+			continue
+		}
+		if err != nil {
+			t.Errorf("parseGoal(%q): %v", tt.input, err)
+			continue
+		}
+		if got != tt.want {
+			t.Errorf("parseGoal(%q) = %+v, want %+v", tt.input, got, tt.want)
+		}
+	}
+}
+
+func TestGoalProgressOf(t *testing.T) {
+	now := time.Date(2026, 10, 18, 12, 0, 0, 0, time.Local)
+	day := func(offset int) string {
+		return now.AddDate(0, 0, offset).Format("2006-01-02")
+	}
+	// doneDays marks the days from offset from to offset to, inclusive
+	doneDays := func(from, to int) map[string]bool {
+		done := make(map[string]bool)
+		for i := from; i <= to; i++ {
+			done[day(i)] = true
+		}
+		return done
+	}
+
+	tests := []struct {
+		name        string
+		goal        Goal
+		streak      int
+		done        map[string]bool
+		wantStatus  goalStatus
+		wantCurrent int
+	}{
+		{
+			name:        "total reached",
+			goal:        Goal{Kind: goalTotal, Target: 3, Start: day(-5)},
+			done:        doneDays(-2, 0),
+			wantStatus:  goalMet,
+			wantCurrent: 3,
+		},
+		{
+			name:        "total open-ended and under way",
+			goal:        Goal{Kind: goalTotal, Target: 10, Start: day(-5)},
+			done:        doneDays(-2, 0),
+			wantStatus:  goalOnTrack,
+			wantCurrent: 3,
+		},
+		{
+			name:        "total ignores check-ins after the deadline",
+			goal:        Goal{Kind: goalTotal, Target: 10, Start: day(-40), Deadline: day(-20)},
+			done:        doneDays(-19, 0),
+			wantStatus:  goalMissed,
+			wantCurrent: 0,
+		},
+		{
+			name:        "total behind pace for its deadline",
+			goal:        Goal{Kind: goalTotal, Target: 20, Start: day(0), Deadline: day(5)},
+			done:        doneDays(0, 0),
+			wantStatus:  goalAtRisk,
+			wantCurrent: 1,
+		},
+		{
+			name:        "streak reached",
+			goal:        Goal{Kind: goalStreak, Target: 3, Start: day(-5)},
+			streak:      3,
+			done:        doneDays(-2, 0),
+			wantStatus:  goalMet,
+			wantCurrent: 3,
+		},
+		{
+			name:        "streak reached only after the deadline",
+			goal:        Goal{Kind: goalStreak, Target: 30, Start: day(-60), Deadline: day(-20)},
+			streak:      35,
+			done:        doneDays(-34, 0),
+			wantStatus:  goalMissed,
+			wantCurrent: 15,
+		},
+		{
+			name:        "streak met before the deadline stays met",
+			goal:        Goal{Kind: goalStreak, Target: 30, Start: day(-60), Deadline: day(-20), MetAt: day(-21)},
+			done:        doneDays(-50, -21),
+			wantStatus:  goalMet,
+			wantCurrent: 0,
+		},
+		{
+			name:        "rate reached",
+			goal:        Goal{Kind: goalRate, Target: 50, Start: "2026-10-01", Deadline: "2026-10-31"},
+			done:        doneDays(-17, 0),
+			wantStatus:  goalMet,
+			wantCurrent: 18,
+		},
+		{
+			name:        "rate out of reach",
+			goal:        Goal{Kind: goalRate, Target: 50, Start: "2026-10-01", Deadline: "2026-10-31"},
+			done:        map[string]bool{},
+			wantStatus:  goalMissed,
+			wantCurrent: 0,
+		},
+	}
+
+	for _, tt := range tests {
+		p := goalProgressOf(tt.goal, Habit{CurrentStreak: tt.streak}, tt.done, now)
+		if p.Status != tt.wantStatus || p.Current != tt.wantCurrent {
+			t.Errorf("%s: status %d, current %d; want status %d, current %d",
+				tt.name, p.Status, p.Current, tt.wantStatus, tt.wantCurrent)
+		}
+	}
+}
@@ -0,0 +1,315 @@
+package main
+
+import (
+	"bytes"
+	"flag"
+	"fmt"
+	"html"
+	"image"
+	"image/color"
+	"image/draw"
+	"image/png"
+	"os"
+	"strconv"
+	"time"
+
+	"github.com/charmbracelet/lipgloss"
+	xdraw "golang.org/x/image/draw"
+	"golang.org/x/image/font"
+	"golang.org/x/image/font/basicfont"
+	"golang.org/x/image/math/fixed"
+)
+
+// ============================================================
+// HEATMAP IMAGES
+// ============================================================
+
+// Geometry of the rendered grid, in pixels at scale 1.
+const (
+	imgCell       = 12
+	imgGap        = 3
+	imgPad        = 16
+	imgLabelWidth = 36
+	imgTitleH     = 24
+	imgMonthH     = 16
+	imgLegendH    = 28
+	imgCharWidth  = 7 // basicfont.Face7x13 advance
+	imgFontAscent = 10
+)
+
+var (
+	imgBackground = lipgloss.Color("#0D1117")
+	imgTitleColor = lipgloss.Color("#7D56F4")
+	imgTextColor  = lipgloss.Color("#AAAAAA")
+	imgWeekend    = lipgloss.Color("#888888")
+	imgTodayColor = lipgloss.Color("#FAFAFA")
+)
+
+type legendEntry struct {
+	Color lipgloss.Color
+	Label string
+}
+
+// heatmapImage is a resolution-independent description of a heatmap that
+// both the SVG and the PNG renderer draw.
+type heatmapImage struct {
+	Width, Height int
+	Rects         []imgRect
+	Labels        []imgLabel
+}
+
+type imgRect struct {
+	X, Y, W, H int
+	Fill       lipgloss.Color
+	Stroke     lipgloss.Color // empty for no outline
+}
+
+type imgLabel struct {
+	X, Y  int // baseline origin
+	Text  string
+	Color lipgloss.Color
+	Bold  bool
+}
+
+// layoutHeatmap places the week-aligned grid from heatmapCells with the same
+// colors, day labels and legend as viewHeatmap, plus month labels.
+func layoutHeatmap(title string, cells [][]heatmapCell, cellColor func(heatmapCell) lipgloss.Color, legend []legendEntry) heatmapImage {
+	numWeeks := len(cells[0])
+	step := imgCell + imgGap
+	gridX := imgPad + imgLabelWidth
+	gridY := imgPad + imgTitleH + imgMonthH
+
+	img := heatmapImage{
+		Width:  gridX + numWeeks*step + imgPad,
+		Height: gridY + 7*step + imgLegendH + imgPad,
+	}
+
+	img.Labels = append(img.Labels, imgLabel{X: imgPad, Y: imgPad + imgFontAscent, Text: title, Color: imgTitleColor, Bold: true})
+
+	// Month labels above the first week that starts in a new month, skipping
+	// any that would overlap the previous label
+	lastMonth := ""
+	nextFree := 0
+	for week := 0; week < numWeeks; week++ {
+		date, err := time.Parse("2006-01-02", cells[0][week].Date)
+		if err != nil {
+			continue
+		}
+		month := date.Format("Jan")
+		if month == lastMonth {
+			continue
+		}
+		lastMonth = month
+
+		x := gridX + week*step
+		if x < nextFree {
+			continue
+		}
+		img.Labels = append(img.Labels, imgLabel{X: x, Y: gridY - 5, Text: month, Color: imgTextColor})
+		nextFree = x + len(month)*imgCharWidth + imgGap
+	}
+
+	days := []string{"Sun", "Mon", "Tue", "Wed", "Thu", "Fri", "Sat"}
+	for day := 0; day < 7; day++ {
+		labelColor := imgTextColor
+		if day == 0 || day == 6 {
+			labelColor = imgWeekend
+		}
+		y := gridY + day*step
+		img.Labels = append(img.Labels, imgLabel{X: imgPad, Y: y + imgFontAscent, Text: days[day], Color: labelColor, Bold: true})
+
+		for week := 0; week < numWeeks; week++ {
+			cell := cells[day][week]
+			if !cell.InRange {
+				continue
+			}
+			rect := imgRect{X: gridX + week*step, Y: y, W: imgCell, H: imgCell, Fill: cellColor(cell)}
+			if cell.Today {
+				rect.Stroke = imgTodayColor
+			}
+			img.Rects = append(img.Rects, rect)
+		}
+	}
+
+	// Legend, followed by the today marker
+	x := gridX
+	y := gridY + 7*step + imgGap*3
+	legend = append(legend, legendEntry{Label: "Today"})
+	for _, entry := range legend {
+		rect := imgRect{X: x, Y: y, W: imgCell, H: imgCell, Fill: entry.Color}
+		if entry.Color == "" {
+			rect.Fill = colorNone
+			rect.Stroke = imgTodayColor
+		}
+		img.Rects = append(img.Rects, rect)
+		img.Labels = append(img.Labels, imgLabel{X: x + imgCell + 6, Y: y + imgFontAscent, Text: entry.Label, Color: imgTextColor})
+		x += imgCell + 6 + len(entry.Label)*imgCharWidth + 16
+	}
+	if x+imgPad > img.Width {
+		img.Width = x + imgPad
+	}
+
+	return img
+}
+
+func (img heatmapImage) SVG() []byte {
+	var b bytes.Buffer
+
+	fmt.Fprintf(&b, `<svg xmlns="http://www.w3.org/2000/svg" width="%d" height="%d" viewBox="0 0 %d %d">`+"\n",
+		img.Width, img.Height, img.Width, img.Height)
+	fmt.Fprintf(&b, `<rect width="100%%" height="100%%" fill="%s"/>`+"\n", imgBackground)
+	b.WriteString(`<g font-family="ui-monospace, SFMono-Regular, Menlo, Consolas, monospace" font-size="11">` + "\n")
+
+	for _, r := range img.Rects {
+		stroke := ""
+		if r.Stroke != "" {
+			stroke = fmt.Sprintf(` stroke="%s" stroke-width="1.5"`, r.Stroke)
+		}
+		fmt.Fprintf(&b, `<rect x="%d" y="%d" width="%d" height="%d" rx="2" fill="%s"%s/>`+"\n",
+			r.X, r.Y, r.W, r.H, r.Fill, stroke)
+	}
+
+	for _, t := range img.Labels {
+		weight := ""
+		if t.Bold {
+			weight = ` font-weight="bold"`
+		}
+		fmt.Fprintf(&b, `<text x="%d" y="%d" fill="%s"%s>%s</text>`+"\n",
+			t.X, t.Y, t.Color, weight, html.EscapeString(t.Text))
+	}
+
+	b.WriteString("</g>\n</svg>\n")
+	return b.Bytes()
+}
+
+// PNG rasterizes the layout with the built-in 7x13 bitmap font and scales
+// it up by an integer factor with nearest-neighbour sampling.
+func (img heatmapImage) PNG(scale int) ([]byte, error) {
+	canvas := image.NewRGBA(image.Rect(0, 0, img.Width, img.Height))
+	draw.Draw(canvas, canvas.Bounds(), image.NewUniform(hexColor(imgBackground)), image.Point{}, draw.Src)
+
+	for _, r := range img.Rects {
+		bounds := image.Rect(r.X, r.Y, r.X+r.W, r.Y+r.H)
+		if r.Stroke != "" {
+			draw.Draw(canvas, bounds.Inset(-1), image.NewUniform(hexColor(r.Stroke)), image.Point{}, draw.Src)
+		}
+		draw.Draw(canvas, bounds, image.NewUniform(hexColor(r.Fill)), image.Point{}, draw.Src)
+	}
+
+	for _, t := range img.Labels {
+		d := &font.Drawer{
+			Dst:  canvas,
+			Src:  image.NewUniform(hexColor(t.Color)),
+			Face: basicfont.Face7x13,
+			Dot:  fixed.P(t.X, t.Y),
+		}
+		d.DrawString(t.Text)
+		if t.Bold {
+			// Faux bold: draw again one pixel to the right
+			d.Dot = fixed.P(t.X+1, t.Y)
+			d.DrawString(t.Text)
+		}
+	}
+
+	out := image.Image(canvas)
+	if scale > 1 {
+		scaled := image.NewRGBA(image.Rect(0, 0, img.Width*scale, img.Height*scale))
+		xdraw.NearestNeighbor.Scale(scaled, scaled.Bounds(), canvas, canvas.Bounds(), draw.Src, nil)
+		out = scaled
+	}
+
+	var b bytes.Buffer
+	if err := png.Encode(&b, out); err != nil {
+		return nil, fmt.Errorf("failed to encode png: %w", err)
+	}
+	return b.Bytes(), nil
+}
+
+// hexColor converts a "#RRGGBB" lipgloss color to an opaque RGBA value.
+func hexColor(c lipgloss.Color) color.RGBA {
+	s := string(c)
+	if len(s) != 7 || s[0] != '#' {
+		return color.RGBA{A: 0xFF}
+	}
+
+	v, err := strconv.ParseUint(s[1:], 16, 32)
+	if err != nil {
+		return color.RGBA{A: 0xFF}
+	}
+
+	return color.RGBA{R: uint8(v >> 16), G: uint8(v >> 8), B: uint8(v), A: 0xFF}
+}
+
+// runHeatmap implements `habit heatmap <name> --svg FILE --png FILE`.
+func runHeatmap(args []string) error {
+	fset := flag.NewFlagSet("heatmap", flag.ContinueOnError)
+	weeks := fset.Int("weeks", 52, "number of weeks to show")
+	svgPath := fset.String("svg", "", "write an SVG image to this file")
+	pngPath := fset.String("png", "", "write a PNG image to this file")
+	scale := fset.Int("scale", 2, "PNG scale factor")
+
+	// Accept the habit before or after the flags
+	var ref string
+	if len(args) > 0 && args[0] != "" && args[0][0] != '-' {
+		ref, args = args[0], args[1:]
+	}
+	if err := fset.Parse(args); err != nil {
+		return err
+	}
+	if ref == "" && fset.NArg() > 0 {
+		ref = fset.Arg(0)
+	}
+
+	if ref == "" || (*svgPath == "" && *pngPath == "") {
+		return fmt.Errorf("usage: habit heatmap <habit> [--weeks N] [--svg FILE] [--png FILE] [--scale N]")
+	}
+	if *weeks < minWeeks || *weeks > maxWeeks {
+		return fmt.Errorf("weeks must be between %d and %d", minWeeks, maxWeeks)
+	}
+	if *scale < 1 {
+		return fmt.Errorf("scale must be at least 1")
+	}
+
+	db, err := NewDatabase()
+	if err != nil {
+		return err
+	}
+	defer db.Close()
+
+	habit, err := db.FindHabit(ref)
+	if err != nil {
+		return err
+	}
+
+	logs, err := db.GetLogs(habit.ID, maxLogDays)
+	if err != nil {
+		return err
+	}
+
+	cells := heatmapCells(logs, *weeks, time.Now())
+	title := fmt.Sprintf("%s - %d day streak", habit.Name, habit.CurrentStreak)
+	img := layoutHeatmap(title, cells, func(c heatmapCell) lipgloss.Color {
+		if c.Done {
+			return colorLevel4
+		}
+		return colorNone
+	}, []legendEntry{{colorNone, "No activity"}, {colorLevel4, "Completed"}})
+
+	if *svgPath != "" {
+		if err := os.WriteFile(*svgPath, img.SVG(), 0644); err != nil {
+			return fmt.Errorf("failed to write %s: %w", *svgPath, err)
+		}
+	}
+
+	if *pngPath != "" {
+		data, err := img.PNG(*scale)
+		if err != nil {
+			return err
+		}
+		if err := os.WriteFile(*pngPath, data, 0644); err != nil {
+			return fmt.Errorf("failed to write %s: %w", *pngPath, err)
+		}
+	}
+
+	return nil
+}
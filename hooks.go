@@ -0,0 +1,146 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strconv"
+	"sync"
+	"time"
+)
+
+// ============================================================
+// HOOK SCRIPTS
+// ============================================================
+
+const defaultHookTimeout = 10 * time.Second
+
+// hookScripts maps event types to the script run from the hooks directory.
+var hookScripts = map[string]string{
+	EventHabitCompleted:      "on-complete",
+	EventStreakMilestone:     "on-streak",
+	EventLevelUp:             "on-level-up",
+	EventAchievementUnlocked: "on-achievement",
+	EventGoalMet:             "on-goal",
+}
+
+// HookRunner executes user scripts from ~/.config/habit-tracker/hooks/.
+// Scripts receive the event as HABIT_* environment variables and as JSON on
+// stdin. Failures are appended to ~/.config/habit-tracker/hooks.log.
+type HookRunner struct {
+	dir     string
+	logPath string
+	timeout time.Duration
+	wg      sync.WaitGroup
+	mu      sync.Mutex // serializes writes to the log file
+}
+
+func NewHookRunner(cfg Config) *HookRunner {
+	r := &HookRunner{timeout: defaultHookTimeout}
+	if cfg.HookTimeout > 0 {
+		r.timeout = time.Duration(cfg.HookTimeout) * time.Second
+	}
+
+	if dir, err := configDir(); err == nil {
+		r.dir = filepath.Join(dir, "hooks")
+		r.logPath = filepath.Join(dir, "hooks.log")
+	}
+
+	return r
+}
+
+// Run starts the script for e in the background if one is installed.
+func (r *HookRunner) Run(e Event) {
+	name, ok := hookScripts[e.Type]
+	if !ok || r.dir == "" {
+		return
+	}
+
+	path := filepath.Join(r.dir, name)
+	info, err := os.Stat(path)
+	if err != nil || info.IsDir() {
+		return
+	}
+	if info.Mode()&0111 == 0 {
+		r.logf("%s: not executable", path)
+		return
+	}
+
+	r.wg.Add(1)
+	go func() {
+		defer r.wg.Done()
+		r.exec(path, e)
+	}()
+}
+
+// Wait blocks until running scripts have exited or timed out.
+func (r *HookRunner) Wait() {
+	r.wg.Wait()
+}
+
+func (r *HookRunner) exec(path string, e Event) {
+	payload, err := json.Marshal(e)
+	if err != nil {
+		r.logf("%s: failed to encode event: %v", path, err)
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), r.timeout)
+	defer cancel()
+
+	var output bytes.Buffer
+	cmd := exec.CommandContext(ctx, path)
+	cmd.Dir = r.dir
+	cmd.Stdin = bytes.NewReader(payload)
+	cmd.Stdout = &output
+	cmd.Stderr = &output
+	cmd.Env = append(os.Environ(), hookEnv(e)...)
+	// Don't let grandchildren holding the output pipe outlive the timeout.
+	cmd.WaitDelay = time.Second
+
+	err = cmd.Run()
+	if ctx.Err() == context.DeadlineExceeded {
+		r.logf("%s: timed out after %s", path, r.timeout)
+		return
+	}
+	if err != nil {
+		r.logf("%s: %v: %s", path, err, bytes.TrimSpace(output.Bytes()))
+	}
+}
+
+func hookEnv(e Event) []string {
+	return []string{
+		"HABIT_EVENT=" + e.Type,
+		"HABIT_ID=" + strconv.Itoa(e.HabitID),
+		"HABIT_NAME=" + e.HabitName,
+		"HABIT_DATE=" + e.Date,
+		"HABIT_STREAK=" + strconv.Itoa(e.Streak),
+		"HABIT_TOTAL=" + strconv.Itoa(e.TotalDone),
+		"HABIT_LEVEL=" + strconv.Itoa(e.Level),
+		"HABIT_XP=" + strconv.Itoa(e.XP),
+		"HABIT_MILESTONE=" + strconv.Itoa(e.Milestone),
+		"HABIT_ACHIEVEMENT=" + e.Achievement,
+		"HABIT_GOAL=" + e.Goal,
+	}
+}
+
+func (r *HookRunner) logf(format string, args ...any) {
+	if r.logPath == "" {
+		return
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	f, err := os.OpenFile(r.logPath, os.O_CREATE|os.O_APPEND|os.O_WRONLY, 0644)
+	if err != nil {
+		return
+	}
+	defer f.Close()
+
+	fmt.Fprintf(f, "%s %s\n", time.Now().Format("2006-01-02 15:04:05"), fmt.Sprintf(format, args...))
+}
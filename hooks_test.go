@@ -0,0 +1,132 @@
+package main
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+// newTestHookRunner runs hooks from a temporary directory with the given
+// scripts, logging next to them.
+func newTestHookRunner(t *testing.T, timeout time.Duration, scripts map[string]string) *HookRunner {
+	t.Helper()
+	dir := t.TempDir()
+	for name, body := range scripts {
+		if err := os.WriteFile(filepath.Join(dir, name), []byte("#!/bin/sh\n"+body+"\n"), 0755); err != nil {
+			t.Fatal(err)
+		}
+	}
+	return &HookRunner{dir: dir, logPath: filepath.Join(dir, "hooks.log"), timeout: timeout}
+}
+
+// hookLog returns what the runner has logged.
+func hookLog(t *testing.T, r *HookRunner) string {
+	t.Helper()
+	log, err := os.ReadFile(r.logPath)
+	if err != nil && !os.IsNotExist(err) {
+		t.Fatal(err)
+	}
+	return string(log)
+}
+
+func TestHookEnvironmentAndStdin(t *testing.T) {
+	r := newTestHookRunner(t, 5*time.Second, map[string]string{
+		"on-complete": "env | grep '^HABIT_' | sort > env.out\ncat > stdin.out",
+	})
+
+	e := Event{
+		Type:      EventHabitCompleted,
+		HabitID:   7,
+		HabitName: "Read a book",
+		Date:      "2026-10-18",
+		Streak:    12,
+		TotalDone: 40,
+		Level:     3,
+		XP:        420,
+		Time:      time.Date(2026, 10, 18, 8, 30, 0, 0, time.UTC),
+	}
+	r.Run(e)
+	r.Wait()
+
+	env, err := os.ReadFile(filepath.Join(r.dir, "env.out"))
+	if err != nil {
+		t.Fatalf("hook did not run: %v (log: %q)", err, hookLog(t, r))
+	}
+	for _, want := range []string{
+		"HABIT_EVENT=habit.completed",
+		"HABIT_ID=7",
+		"HABIT_NAME=Read a book",
+		"HABIT_DATE=2026-10-18",
+		"HABIT_STREAK=12",
+		"HABIT_TOTAL=40",
+		"HABIT_LEVEL=3",
+		"HABIT_XP=420",
+		"HABIT_MILESTONE=0",
+		"HABIT_ACHIEVEMENT=",
+		"HABIT_GOAL=",
+	} {
+		if !strings.Contains("\n"+string(env), "\n"+want+"\n") {
+			t.Errorf("hook environment has no %s:\n%s", want, env)
+		}
+	}
+
+	stdin, err := os.ReadFile(filepath.Join(r.dir, "stdin.out"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	var got Event
+	if err := json.Unmarshal(stdin, &got); err != nil {
+		t.Fatalf("hook stdin is not an event: %v: %s", err, stdin)
+	}
+	if got != e {
+		t.Errorf("hook stdin = %+v, want %+v", got, e)
+	}
+
+	if log := hookLog(t, r); log != "" {
+		t.Errorf("successful hook logged %q", log)
+	}
+}
+
+func TestHookTimeoutIsKilledAndLogged(t *testing.T) {
+	r := newTestHookRunner(t, 100*time.Millisecond, map[string]string{
+		"on-level-up": "exec sleep 30",
+	})
+
+	start := time.Now()
+	r.Run(Event{Type: EventLevelUp})
+	r.Wait()
+
+	if elapsed := time.Since(start); elapsed > 5*time.Second {
+		t.Errorf("Wait returned after %v, want the hook killed after its timeout", elapsed)
+	}
+	if log := hookLog(t, r); !strings.Contains(log, "on-level-up: timed out after 100ms") {
+		t.Errorf("hooks.log = %q, want the timeout logged", log)
+	}
+}
+
+func TestHookFailuresAreLogged(t *testing.T) {
+	r := newTestHookRunner(t, 5*time.Second, map[string]string{
+		"on-streak": "echo boom >&2\nexit 3",
+	})
+	if err := os.WriteFile(filepath.Join(r.dir, "on-goal"), []byte("#!/bin/sh\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	r.Run(Event{Type: EventStreakMilestone})
+	r.Run(Event{Type: EventGoalMet})
+	r.Run(Event{Type: EventAchievementUnlocked}) // no script installed
+	r.Wait()
+
+	log := hookLog(t, r)
+	for _, want := range []string{"on-streak: exit status 3: boom", "on-goal: not executable"} {
+		if !strings.Contains(log, want) {
+			t.Errorf("hooks.log = %q, want a line containing %q", log, want)
+		}
+	}
+	if n := strings.Count(log, "\n"); n != 2 {
+		t.Errorf("hooks.log has %d lines, want 2:\n%s", n, log)
+	}
+}
@@ -0,0 +1,260 @@
+package main
+
+import (
+	"bufio"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+	"time"
+)
+
+// ============================================================
+// ICALENDAR EXPORT
+// ============================================================
+
+const (
+	icsDateTime      = "20060102T150405"
+	icsDate          = "20060102"
+	icsMaxLineOctets = 75
+)
+
+type exportLog struct {
+	HabitID   int
+	HabitName string
+	Date      string
+	Timestamp string
+}
+
+// GetAllLogs returns every check-in with its habit name, oldest first.
+func (d *Database) GetAllLogs() ([]exportLog, error) {
+	rows, err := d.db.Query(`
+		SELECT l.habit_id, h.name, l.date, COALESCE(l.timestamp, '')
+		FROM logs l
+		JOIN habits h ON h.id = l.habit_id
+		ORDER BY l.date, l.habit_id
+	`)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get logs: %w", err)
+	}
+	defer rows.Close()
+
+	var logs []exportLog
+	for rows.Next() {
+		var l exportLog
+		if err := rows.Scan(&l.HabitID, &l.HabitName, &l.Date, &l.Timestamp); err != nil {
+			return nil, fmt.Errorf("failed to scan log: %w", err)
+		}
+		logs = append(logs, l)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating logs: %w", err)
+	}
+
+	return logs, nil
+}
+
+// writeICS writes a calendar with one VEVENT per check-in and one daily
+// recurring VTODO per habit, plus a completed instance of it for habits
+// done today. Times are floating (no time zone), matching how timestamps
+// are stored.
+func writeICS(w io.Writer, db *Database, now time.Time) error {
+	habits, err := db.GetHabits()
+	if err != nil {
+		return err
+	}
+
+	logs, err := db.GetAllLogs()
+	if err != nil {
+		return err
+	}
+
+	reminders, err := db.GetReminders()
+	if err != nil {
+		return err
+	}
+	reminderAt := make(map[int]string)
+	for _, r := range reminders {
+		reminderAt[r.HabitID] = r.Time
+	}
+
+	// When each habit was checked off today
+	today := now.Format("2006-01-02")
+	completedAt := make(map[int]time.Time)
+	for _, l := range logs {
+		if l.Date != today {
+			continue
+		}
+		at, err := time.ParseInLocation("2006-01-02 15:04:05", l.Timestamp, time.Local)
+		if err != nil {
+			at = now
+		}
+		completedAt[l.HabitID] = at
+	}
+
+	stamp := now.UTC().Format(icsDateTime) + "Z"
+	cal := &icsWriter{w: bufio.NewWriter(w)}
+
+	cal.line("BEGIN:VCALENDAR")
+	cal.line("VERSION:2.0")
+	cal.line("PRODID:-//Habit Tracker//EN")
+	cal.line("CALSCALE:GREGORIAN")
+	cal.line("X-WR-CALNAME:Habits")
+
+	for _, l := range logs {
+		day, err := time.Parse("2006-01-02", l.Date)
+		if err != nil {
+			continue
+		}
+
+		// Backfilled check-ins carry the time they were entered, not a time
+		// on the logged day, so they become all-day events.
+		start, err := time.Parse("2006-01-02 15:04:05", l.Timestamp)
+		timed := err == nil && strings.HasPrefix(l.Timestamp, l.Date)
+
+		cal.line("BEGIN:VEVENT")
+		cal.line(fmt.Sprintf("UID:log-%d-%s@habit-tracker", l.HabitID, l.Date))
+		cal.line("DTSTAMP:" + stamp)
+		if timed {
+			cal.line("DTSTART:" + start.Format(icsDateTime))
+			cal.line("DURATION:PT15M")
+		} else {
+			cal.line("DTSTART;VALUE=DATE:" + day.Format(icsDate))
+		}
+		cal.line("SUMMARY:" + icsEscape("✓ "+l.HabitName))
+		cal.line("CATEGORIES:Habit")
+		cal.line("TRANSP:TRANSPARENT")
+		cal.line("END:VEVENT")
+	}
+
+	for _, h := range habits {
+		// created_at is SQLite's CURRENT_TIMESTAMP, in UTC
+		created, err := time.ParseInLocation("2006-01-02 15:04:05", h.CreatedAt, time.UTC)
+		if err != nil {
+			created = now
+		}
+		created = created.Local()
+
+		// occurrence formats the day's instance as DTSTART and RECURRENCE-ID
+		// expect it, timed at the reminder if there is one.
+		occurrence := func(day time.Time) string {
+			if at, ok := reminderAt[h.ID]; ok {
+				clock, _ := time.Parse("15:04", at)
+				start := time.Date(day.Year(), day.Month(), day.Day(), clock.Hour(), clock.Minute(), 0, 0, time.Local)
+				return ":" + start.Format(icsDateTime)
+			}
+			return ";VALUE=DATE:" + day.Format(icsDate)
+		}
+
+		uid := fmt.Sprintf("UID:habit-%d@habit-tracker", h.ID)
+		description := "DESCRIPTION:" + icsEscape(fmt.Sprintf("Current streak: %d days\nTotal completions: %d\nLevel %d",
+			h.CurrentStreak, h.TotalDone, h.Level))
+
+		cal.line("BEGIN:VTODO")
+		cal.line(uid)
+		cal.line("DTSTAMP:" + stamp)
+		cal.line("DTSTART" + occurrence(created))
+		cal.line("RRULE:FREQ=DAILY")
+		cal.line("SUMMARY:" + icsEscape(h.Name))
+		cal.line(description)
+		cal.line("STATUS:NEEDS-ACTION")
+		cal.line("END:VTODO")
+
+		// A status on the series would complete every day, so today's
+		// completion overrides just today's instance
+		if at, ok := completedAt[h.ID]; ok {
+			cal.line("BEGIN:VTODO")
+			cal.line(uid)
+			cal.line("DTSTAMP:" + stamp)
+			cal.line("RECURRENCE-ID" + occurrence(now))
+			cal.line("DTSTART" + occurrence(now))
+			cal.line("SUMMARY:" + icsEscape(h.Name))
+			cal.line(description)
+			cal.line("STATUS:COMPLETED")
+			cal.line("COMPLETED:" + at.UTC().Format(icsDateTime) + "Z")
+			cal.line("END:VTODO")
+		}
+	}
+
+	cal.line("END:VCALENDAR")
+
+	if cal.err != nil {
+		return cal.err
+	}
+	return cal.w.Flush()
+}
+
+// icsWriter writes CRLF-terminated content lines folded at 75 octets.
+type icsWriter struct {
+	w   *bufio.Writer
+	err error
+}
+
+func (c *icsWriter) line(s string) {
+	if c.err != nil {
+		return
+	}
+
+	var b strings.Builder
+	width := 0
+	for _, r := range s {
+		size := len(string(r))
+		if width+size > icsMaxLineOctets {
+			b.WriteString("\r\n ")
+			width = 1
+		}
+		b.WriteRune(r)
+		width += size
+	}
+	b.WriteString("\r\n")
+
+	_, c.err = c.w.WriteString(b.String())
+}
+
+func icsEscape(s string) string {
+	return strings.NewReplacer(
+		`\`, `\\`,
+		";", `\;`,
+		",", `\,`,
+		"\n", `\n`,
+	).Replace(s)
+}
+
+// runExport implements `habit export`.
+func runExport(args []string) error {
+	fset := flag.NewFlagSet("export", flag.ContinueOnError)
+	format := fset.String("format", "ics", "export format (ics)")
+	output := fset.String("o", "-", "output file, - for stdout")
+	if err := fset.Parse(args); err != nil {
+		return err
+	}
+
+	if *format != "ics" {
+		return fmt.Errorf("unsupported export format %q", *format)
+	}
+
+	db, err := NewDatabase()
+	if err != nil {
+		return err
+	}
+	defer db.Close()
+
+	if *output == "-" {
+		return writeICS(os.Stdout, db, time.Now())
+	}
+
+	f, err := os.Create(*output)
+	if err != nil {
+		return fmt.Errorf("failed to create %s: %w", *output, err)
+	}
+	if err := writeICS(f, db, time.Now()); err != nil {
+		f.Close()
+		return err
+	}
+	if err := f.Close(); err != nil {
+		return fmt.Errorf("failed to write %s: %w", *output, err)
+	}
+	return nil
+}
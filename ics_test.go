@@ -0,0 +1,59 @@
+package main
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestWriteICSCompletesOnlyToday(t *testing.T) {
+	// Two hours ahead of UTC, so a habit created late in the UTC evening
+	// starts the next local day
+	local := time.Local
+	time.Local = time.FixedZone("UTC+2", 2*60*60)
+	t.Cleanup(func() { time.Local = local })
+
+	db := newTestDatabase(t)
+	if err := db.AddHabit("Read"); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := db.db.Exec("UPDATE habits SET created_at = '2026-10-01 23:30:00'"); err != nil {
+		t.Fatal(err)
+	}
+	habits, err := db.GetHabits()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	now := time.Now()
+	if _, err := db.ToggleHabit(habits[0].ID, now.Format("2006-01-02")); err != nil {
+		t.Fatal(err)
+	}
+
+	var buf bytes.Buffer
+	if err := writeICS(&buf, db, now); err != nil {
+		t.Fatal(err)
+	}
+	out := strings.ReplaceAll(buf.String(), "\r\n ", "")
+
+	if !strings.Contains(out, "DTSTART;VALUE=DATE:20261002\r\n") {
+		t.Errorf("series should start on the local creation day, Oct 2:\n%s", out)
+	}
+	if n := strings.Count(out, "STATUS:COMPLETED"); n != 1 {
+		t.Fatalf("got %d completed to-dos, want 1:\n%s", n, out)
+	}
+
+	// The completion is today's instance, not the whole series
+	todos := strings.Split(out, "BEGIN:VTODO")[1:]
+	if len(todos) != 2 {
+		t.Fatalf("got %d to-dos, want the series and today's instance", len(todos))
+	}
+	if strings.Contains(todos[0], "STATUS:COMPLETED") || !strings.Contains(todos[0], "RRULE:FREQ=DAILY") {
+		t.Errorf("series to-do:\n%s", todos[0])
+	}
+	wantID := "RECURRENCE-ID;VALUE=DATE:" + now.Format(icsDate) + "\r\n"
+	if !strings.Contains(todos[1], wantID) || strings.Contains(todos[1], "RRULE") || !strings.Contains(todos[1], "COMPLETED:") {
+		t.Errorf("today's to-do should be a completed instance with %q:\n%s", wantID, todos[1])
+	}
+}
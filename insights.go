@@ -0,0 +1,298 @@
+package main
+
+import (
+	"cmp"
+	"fmt"
+	"math"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/charmbracelet/bubbles/key"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+)
+
+// ============================================================
+// INSIGHTS
+// ============================================================
+
+// insightRanges are the periods, in days, the insights cycle through.
+var insightRanges = []int{30, 90, 365}
+
+const (
+	// minInsightDays is the fewest days two habits must share before they
+	// are compared.
+	minInsightDays = 14
+	// minInsightGroup is the fewest days on each side of a comparison
+	// (with and without the habit) for it to be reported.
+	minInsightGroup = 3
+	// minCorrelation hides relationships too weak to mean anything.
+	minCorrelation = 0.1
+	// maxInsights is how many relationships are listed each way.
+	maxInsights = 5
+)
+
+// insight is a relationship between two habits over the days both were
+// tracked. Value insights relate one habit being done to the numbers
+// recorded on another, such as a daily mood rating.
+type insight struct {
+	A, B    Habit
+	R       float64 // Pearson correlation, -1 to 1
+	N       int     // days compared
+	Value   bool    // B's recorded values rather than B being done
+	With    float64 // B's completion rate or mean value on days A was done
+	Without float64 // the same on days A was not done
+}
+
+// GetValuesSince returns every value recorded on or after start, by habit
+// ID and date.
+func (d *Database) GetValuesSince(start string) (map[int]map[string]float64, error) {
+	rows, err := d.db.Query("SELECT habit_id, date, value FROM logs WHERE date >= ? AND value IS NOT NULL", start)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get values since %s: %w", start, err)
+	}
+	defer rows.Close()
+
+	values := make(map[int]map[string]float64)
+	for rows.Next() {
+		var id int
+		var date string
+		var value float64
+		if err := rows.Scan(&id, &date, &value); err != nil {
+			return nil, fmt.Errorf("failed to scan value: %w", err)
+		}
+		if values[id] == nil {
+			values[id] = make(map[string]float64)
+		}
+		values[id][date] = value
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating values: %w", err)
+	}
+
+	return values, nil
+}
+
+// pearson returns the correlation of xs and ys, and false when either
+// never varies.
+func pearson(xs, ys []float64) (float64, bool) {
+	n := float64(len(xs))
+	if n == 0 {
+		return 0, false
+	}
+	mx, my := mean(xs), mean(ys)
+	var cov, vx, vy float64
+	for i := range xs {
+		dx, dy := xs[i]-mx, ys[i]-my
+		cov += dx * dy
+		vx += dx * dx
+		vy += dy * dy
+	}
+	if vx == 0 || vy == 0 {
+		return 0, false
+	}
+	return cov / math.Sqrt(vx*vy), true
+}
+
+// compareHabits relates a being done to b over days: to b being done, or
+// to b's recorded values when values is set. Days a value is missing for
+// are left out.
+func compareHabits(a, b Habit, days []string, done map[int]map[string]bool, values map[int]map[string]float64, byValue bool) (insight, bool) {
+	var xs, ys []float64
+	var with, without []float64
+	for _, day := range days {
+		var y float64
+		if byValue {
+			v, ok := values[b.ID][day]
+			if !ok {
+				continue
+			}
+			y = v
+		} else if done[b.ID][day] {
+			y = 1
+		}
+
+		x := 0.0
+		if done[a.ID][day] {
+			x = 1
+			with = append(with, y)
+		} else {
+			without = append(without, y)
+		}
+		xs = append(xs, x)
+		ys = append(ys, y)
+	}
+
+	if len(xs) < minInsightDays || len(with) < minInsightGroup || len(without) < minInsightGroup {
+		return insight{}, false
+	}
+	r, ok := pearson(xs, ys)
+	if !ok || math.Abs(r) < minCorrelation {
+		return insight{}, false
+	}
+
+	in := insight{A: a, B: b, R: r, N: len(xs), Value: byValue, With: mean(with), Without: mean(without)}
+	if !byValue {
+		in.With *= 100
+		in.Without *= 100
+	}
+	return in, true
+}
+
+// findInsights compares every pair of habits over the days from start to
+// yesterday that both were tracked, strongest first. Today is left out as
+// it is not over yet.
+func findInsights(habits []Habit, starts map[int]string, done map[int]map[string]bool, values map[int]map[string]float64, start, now time.Time) []insight {
+	var insights []insight
+	for i, a := range habits {
+		for j, b := range habits {
+			if i == j {
+				continue
+			}
+
+			first := max(starts[a.ID], starts[b.ID], start.Format("2006-01-02"))
+			var days []string
+			for d := startOfDay(now).AddDate(0, 0, -1); d.Format("2006-01-02") >= first; d = d.AddDate(0, 0, -1) {
+				days = append(days, d.Format("2006-01-02"))
+			}
+
+			// Done-together is symmetric, so each pair is compared once
+			if i < j {
+				if in, ok := compareHabits(a, b, days, done, values, false); ok {
+					insights = append(insights, in)
+				}
+			}
+			if len(values[b.ID]) > 0 {
+				if in, ok := compareHabits(a, b, days, done, values, true); ok {
+					insights = append(insights, in)
+				}
+			}
+		}
+	}
+
+	slices.SortStableFunc(insights, func(x, y insight) int {
+		return cmp.Compare(math.Abs(y.R), math.Abs(x.R))
+	})
+	return insights
+}
+
+// correlationLabel puts the size of a correlation into words.
+func correlationLabel(r float64) string {
+	switch r = math.Abs(r); {
+	case r >= 0.5:
+		return "strong"
+	case r >= 0.3:
+		return "moderate"
+	}
+	return "weak"
+}
+
+// openInsights shows how the habits in the list relate to each other.
+func (m *Model) openInsights() {
+	if len(m.habits) < 2 {
+		m.setMessage("Insights need at least two habits", "info")
+		return
+	}
+	if err := m.loadInsights(); err != nil {
+		m.setError(err)
+		return
+	}
+	m.mode = modeInsights
+}
+
+// loadInsights recomputes the insights for the chosen period.
+func (m *Model) loadInsights() error {
+	now := time.Now()
+	start := now.AddDate(0, 0, -insightRanges[m.insightRange])
+	from := start.Format("2006-01-02")
+
+	done, err := m.db.GetLogsSince(from)
+	if err != nil {
+		return err
+	}
+	values, err := m.db.GetValuesSince(from)
+	if err != nil {
+		return err
+	}
+	starts, err := m.db.GetHabitStarts()
+	if err != nil {
+		return err
+	}
+
+	m.insights = findInsights(m.habits, starts, done, values, start, now)
+	return nil
+}
+
+func (m *Model) updateInsights(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	m.message = ""
+	m.err = nil
+
+	switch {
+	case key.Matches(msg, m.keys.Back, m.keys.Insights):
+		m.mode = modeList
+
+	case key.Matches(msg, m.keys.Help):
+		m.showHelp()
+
+	case key.Matches(msg, m.keys.Range):
+		m.insightRange = (m.insightRange + 1) % len(insightRanges)
+		if err := m.loadInsights(); err != nil {
+			m.setError(err)
+		}
+	}
+	return m, nil
+}
+
+// viewInsights lists the strongest positive and negative relationships
+// between the list's habits, each with the days it rests on.
+func (m *Model) viewInsights() string {
+	var s strings.Builder
+
+	s.WriteString(titleStyle.Render(fmt.Sprintf("%sInsights: last %d days", glyph("🔗 ", ""), insightRanges[m.insightRange])) + "\n\n")
+
+	var positive, negative []insight
+	for _, in := range m.insights {
+		if in.R > 0 && len(positive) < maxInsights {
+			positive = append(positive, in)
+		} else if in.R < 0 && len(negative) < maxInsights {
+			negative = append(negative, in)
+		}
+	}
+
+	section := func(title string, insights []insight, style lipgloss.Style, empty string) {
+		s.WriteString(subtitleStyle.Render(title) + "\n\n")
+		if len(insights) == 0 {
+			s.WriteString(dimStyle.Render("  "+empty) + "\n\n")
+			return
+		}
+		for _, in := range insights {
+			pair := in.A.Name + glyph(" ↔ ", " & ") + in.B.Name
+			if in.Value {
+				pair = in.A.Name + glyph(" → ", " -> ") + in.B.Name + " value"
+			}
+			s.WriteString("  " + style.Render(fmt.Sprintf("%+.2f", in.R)) + "  " +
+				normalStyle.Render(pair) + "  " +
+				dimStyle.Render(fmt.Sprintf("%s%s%s", correlationLabel(in.R), glyph(" · ", " | "), plural(in.N, "day"))) + "\n")
+
+			detail := fmt.Sprintf("%s done %.0f%% of days with %s, %.0f%% without", in.B.Name, in.With, in.A.Name, in.Without)
+			if in.Value {
+				detail = fmt.Sprintf("%s averages %.1f on days with %s, %.1f without", in.B.Name, in.With, in.A.Name, in.Without)
+			}
+			s.WriteString(dimStyle.Render("         "+detail) + "\n")
+		}
+		s.WriteString("\n")
+	}
+
+	section(glyph("📈 ", "")+"Go together", positive, successStyle, "No habits tend to be done together yet.")
+	section(glyph("📉 ", "")+"Work against each other", negative, errorStyle, "No habits tend to crowd each other out yet.")
+
+	s.WriteString(dimStyle.Render(fmt.Sprintf(
+		"Pairs need %d shared days; values come from the numbers recorded in the calendar.\nA correlation shows habits move together, not that one causes the other.",
+		minInsightDays)) + "\n\n")
+
+	s.WriteString(m.renderHelp())
+
+	return s.String()
+}
@@ -0,0 +1,356 @@
+package main
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/charmbracelet/bubbles/help"
+	"github.com/charmbracelet/bubbles/key"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+	"github.com/charmbracelet/x/ansi"
+)
+
+// ============================================================
+// KEY BINDINGS
+// ============================================================
+
+// keyMap holds every binding in the TUI. Each one can be rebound from the
+// "keys" object in config.json using the name in keyMap.named.
+type keyMap struct {
+	// List
+	Up          key.Binding
+	Down        key.Binding
+	Top         key.Binding
+	Bottom      key.Binding
+	Toggle      key.Binding
+	Add         key.Binding
+	Delete      key.Binding
+	Heatmap     key.Binding
+	AllHabits   key.Binding
+	Category    key.Binding
+	Tags        key.Binding
+	TagFilter   key.Binding
+	Search      key.Binding
+	ClearSearch key.Binding
+	Sort        key.Binding
+	MoveUp      key.Binding
+	MoveDown    key.Binding
+	Summary     key.Binding
+	Calendar    key.Binding
+	Overview    key.Binding
+	Charts      key.Binding
+	Timing      key.Binding
+	Insights    key.Binding
+	Goals       key.Binding
+	Help        key.Binding
+	Quit        key.Binding
+
+	// Heatmap, summary and help screens
+	WeeksLess key.Binding
+	WeeksMore key.Binding
+	Back      key.Binding
+
+	// Calendar
+	PrevDay   key.Binding
+	NextDay   key.Binding
+	PrevMonth key.Binding
+	NextMonth key.Binding
+	Today     key.Binding
+	Note      key.Binding
+	Value     key.Binding
+
+	// Overview
+	Range key.Binding
+
+	// Charts
+	PrevChart key.Binding
+	NextChart key.Binding
+
+	// Delete confirmation
+	Yes key.Binding
+	No  key.Binding
+
+	// Text prompts
+	Submit    key.Binding
+	Cancel    key.Binding
+	PrevMatch key.Binding
+	NextMatch key.Binding
+}
+
+func newKeyMap() keyMap {
+	return keyMap{
+		Up:          key.NewBinding(key.WithKeys("up", "k"), key.WithHelp(glyph("↑", "up")+"/k", "up")),
+		Down:        key.NewBinding(key.WithKeys("down", "j"), key.WithHelp(glyph("↓", "down")+"/j", "down")),
+		Top:         key.NewBinding(key.WithKeys("g", "home"), key.WithHelp("g", "first")),
+		Bottom:      key.NewBinding(key.WithKeys("G", "end"), key.WithHelp("G", "last")),
+		Toggle:      key.NewBinding(key.WithKeys("enter", " "), key.WithHelp("enter", "toggle/fold")),
+		Add:         key.NewBinding(key.WithKeys("a"), key.WithHelp("a", "add")),
+		Delete:      key.NewBinding(key.WithKeys("d"), key.WithHelp("d", "delete")),
+		Heatmap:     key.NewBinding(key.WithKeys("h"), key.WithHelp("h", "heatmap")),
+		AllHabits:   key.NewBinding(key.WithKeys("H"), key.WithHelp("H", "all habits heatmap")),
+		Category:    key.NewBinding(key.WithKeys("c"), key.WithHelp("c", "category")),
+		Tags:        key.NewBinding(key.WithKeys("T"), key.WithHelp("T", "tags")),
+		TagFilter:   key.NewBinding(key.WithKeys("t"), key.WithHelp("t", "filter by tag")),
+		Search:      key.NewBinding(key.WithKeys("/"), key.WithHelp("/", "search")),
+		ClearSearch: key.NewBinding(key.WithKeys("esc"), key.WithHelp("esc", "clear search")),
+		Sort:        key.NewBinding(key.WithKeys("s"), key.WithHelp("s", "sort")),
+		MoveUp:      key.NewBinding(key.WithKeys("K"), key.WithHelp("K", "move up")),
+		MoveDown:    key.NewBinding(key.WithKeys("J"), key.WithHelp("J", "move down")),
+		Summary:     key.NewBinding(key.WithKeys("v"), key.WithHelp("v", "summary")),
+		Calendar:    key.NewBinding(key.WithKeys("m"), key.WithHelp("m", "calendar")),
+		Overview:    key.NewBinding(key.WithKeys("o"), key.WithHelp("o", "overview")),
+		Charts:      key.NewBinding(key.WithKeys("C"), key.WithHelp("C", "charts")),
+		Timing:      key.NewBinding(key.WithKeys("w"), key.WithHelp("w", "time of day")),
+		Insights:    key.NewBinding(key.WithKeys("i"), key.WithHelp("i", "insights")),
+		Goals:       key.NewBinding(key.WithKeys("p"), key.WithHelp("p", "goals")),
+		Help:        key.NewBinding(key.WithKeys("?"), key.WithHelp("?", "help")),
+		Quit:        key.NewBinding(key.WithKeys("q"), key.WithHelp("q", "quit")),
+
+		WeeksLess: key.NewBinding(key.WithKeys("left"), key.WithHelp(glyph("←", "left"), "fewer weeks")),
+		WeeksMore: key.NewBinding(key.WithKeys("right"), key.WithHelp(glyph("→", "right"), "more weeks")),
+		Back:      key.NewBinding(key.WithKeys("esc", "q"), key.WithHelp("esc/q", "back")),
+
+		PrevDay:   key.NewBinding(key.WithKeys("left", "h"), key.WithHelp(glyph("←", "left")+"/h", "previous day")),
+		NextDay:   key.NewBinding(key.WithKeys("right", "l"), key.WithHelp(glyph("→", "right")+"/l", "next day")),
+		PrevMonth: key.NewBinding(key.WithKeys("[", "pgup"), key.WithHelp("[", "previous month")),
+		NextMonth: key.NewBinding(key.WithKeys("]", "pgdown"), key.WithHelp("]", "next month")),
+		Today:     key.NewBinding(key.WithKeys("t"), key.WithHelp("t", "today")),
+		Note:      key.NewBinding(key.WithKeys("n"), key.WithHelp("n", "note")),
+		Value:     key.NewBinding(key.WithKeys("#"), key.WithHelp("#", "value")),
+
+		Range: key.NewBinding(key.WithKeys("r"), key.WithHelp("r", "7/14/30 days")),
+
+		PrevChart: key.NewBinding(key.WithKeys("left", "h", "shift+tab"), key.WithHelp(glyph("←", "left")+"/h", "previous chart")),
+		NextChart: key.NewBinding(key.WithKeys("right", "l", "tab"), key.WithHelp(glyph("→", "right")+"/l", "next chart")),
+
+		Yes: key.NewBinding(key.WithKeys("y", "Y"), key.WithHelp("y", "yes")),
+		No:  key.NewBinding(key.WithKeys("n", "N", "esc"), key.WithHelp("n", "no")),
+
+		Submit:    key.NewBinding(key.WithKeys("enter"), key.WithHelp("enter", "save")),
+		Cancel:    key.NewBinding(key.WithKeys("esc"), key.WithHelp("esc", "cancel")),
+		PrevMatch: key.NewBinding(key.WithKeys("up", "ctrl+p"), key.WithHelp(glyph("↑", "up"), "previous")),
+		NextMatch: key.NewBinding(key.WithKeys("down", "ctrl+n"), key.WithHelp(glyph("↓", "down"), "next")),
+	}
+}
+
+// named maps config names to bindings.
+func (k *keyMap) named() map[string]*key.Binding {
+	return map[string]*key.Binding{
+		"up": &k.Up, "down": &k.Down, "top": &k.Top, "bottom": &k.Bottom,
+		"toggle": &k.Toggle, "add": &k.Add, "delete": &k.Delete, "heatmap": &k.Heatmap, "all_habits": &k.AllHabits,
+		"category": &k.Category, "tags": &k.Tags, "tag_filter": &k.TagFilter,
+		"search": &k.Search, "clear_search": &k.ClearSearch, "sort": &k.Sort,
+		"move_up": &k.MoveUp, "move_down": &k.MoveDown, "summary": &k.Summary,
+		"calendar": &k.Calendar, "overview": &k.Overview, "charts": &k.Charts, "timing": &k.Timing, "insights": &k.Insights, "goals": &k.Goals, "help": &k.Help, "quit": &k.Quit,
+		"weeks_less": &k.WeeksLess, "weeks_more": &k.WeeksMore, "back": &k.Back,
+		"prev_day": &k.PrevDay, "next_day": &k.NextDay, "prev_month": &k.PrevMonth, "next_month": &k.NextMonth,
+		"today": &k.Today, "note": &k.Note, "value": &k.Value, "range": &k.Range,
+		"prev_chart": &k.PrevChart, "next_chart": &k.NextChart,
+		"yes": &k.Yes, "no": &k.No,
+		"submit": &k.Submit, "cancel": &k.Cancel, "prev_match": &k.PrevMatch, "next_match": &k.NextMatch,
+	}
+}
+
+// groups are bindings that are live at the same time and so must not
+// share keys.
+func (k *keyMap) groups() map[string][]string {
+	return map[string][]string{
+		"list": {"up", "down", "top", "bottom", "toggle", "add", "delete", "heatmap", "all_habits", "category", "tags",
+			"tag_filter", "search", "clear_search", "sort", "move_up", "move_down", "summary", "calendar", "overview", "charts", "timing", "insights", "goals", "help", "quit"},
+		"heatmap": {"weeks_less", "weeks_more", "heatmap", "all_habits", "back", "help"},
+		"calendar": {"up", "down", "prev_day", "next_day", "prev_month", "next_month", "today",
+			"toggle", "note", "value", "back", "calendar", "help"},
+		"overview": {"up", "down", "prev_day", "next_day", "today", "range", "toggle", "back", "overview", "help"},
+		"charts":   {"prev_chart", "next_chart", "back", "charts", "help"},
+		"timing":   {"back", "timing", "help"},
+		"insights": {"range", "back", "insights", "help"},
+		"goals":    {"up", "down", "add", "delete", "back", "goals", "help"},
+		"summary":  {"up", "down", "back", "summary"},
+		"help":     {"back", "help"},
+		"confirm":  {"yes", "no"},
+		"search":   {"submit", "cancel", "prev_match", "next_match"},
+	}
+}
+
+// loadKeyMap applies user overrides to the default bindings. An empty key
+// list unbinds an action.
+func loadKeyMap(overrides map[string][]string) (keyMap, error) {
+	k := newKeyMap()
+	named := k.named()
+
+	for name, keys := range overrides {
+		b, ok := named[name]
+		if !ok {
+			return k, fmt.Errorf("keys: unknown action %q", name)
+		}
+		if len(keys) == 0 {
+			b.SetEnabled(false)
+			continue
+		}
+		b.SetKeys(keys...)
+		b.SetHelp(keyLabel(keys), b.Help().Desc)
+	}
+
+	for group, names := range k.groups() {
+		owner := make(map[string]string)
+		for _, name := range names {
+			b := named[name]
+			if !b.Enabled() {
+				continue
+			}
+			for _, kk := range b.Keys() {
+				if other, taken := owner[kk]; taken {
+					return k, fmt.Errorf("keys: %q is bound to both %s and %s in the %s screen", kk, other, name, group)
+				}
+				owner[kk] = name
+			}
+		}
+	}
+
+	return k, nil
+}
+
+// keyLabel is how a binding's keys are shown in help.
+func keyLabel(keys []string) string {
+	labels := make([]string, len(keys))
+	for i, k := range keys {
+		switch k {
+		case " ":
+			labels[i] = "space"
+		case "up":
+			labels[i] = glyph("↑", "up")
+		case "down":
+			labels[i] = glyph("↓", "down")
+		case "left":
+			labels[i] = glyph("←", "left")
+		case "right":
+			labels[i] = glyph("→", "right")
+		default:
+			labels[i] = k
+		}
+	}
+	return strings.Join(labels, "/")
+}
+
+// shortHelp lists the bindings worth showing in the footer of each mode.
+func (m *Model) shortHelp() []key.Binding {
+	k := m.keys
+	switch m.mode {
+	case modeHeatmap:
+		allHabits := withHelpDesc(k.AllHabits, "all habits")
+		if m.heatmapAll {
+			allHabits = withHelpDesc(k.AllHabits, "selected habit")
+		}
+		return []key.Binding{k.WeeksLess, k.WeeksMore, allHabits, k.Back, k.Help}
+	case modeDelete:
+		return []key.Binding{k.Yes, k.No}
+	case modeCalendar:
+		return []key.Binding{k.PrevDay, k.NextDay, withHelpDesc(k.Up, "previous week"), withHelpDesc(k.Down, "next week"),
+			k.PrevMonth, k.NextMonth, k.Today, withHelpDesc(k.Toggle, "toggle day"), k.Note, k.Value, k.Back, k.Help}
+	case modeMatrix:
+		return []key.Binding{k.Up, k.Down, k.PrevDay, k.NextDay, k.Today, withHelpDesc(k.Toggle, "toggle day"), k.Range, k.Back, k.Help}
+	case modeChart:
+		return []key.Binding{k.PrevChart, k.NextChart, k.Back, k.Help}
+	case modeTiming:
+		return []key.Binding{k.Back, k.Help}
+	case modeInsights:
+		return []key.Binding{withHelpDesc(k.Range, "30/90/365 days"), k.Back, k.Help}
+	case modeGoals:
+		return []key.Binding{k.Up, k.Down, withHelpDesc(k.Add, "add goal"), withHelpDesc(k.Delete, "delete goal"), k.Back, k.Help}
+	case modeAdd, modeCategory, modeTags, modeNote, modeValue, modeGoalAdd:
+		return []key.Binding{k.Submit, k.Cancel}
+	case modeSearch:
+		return []key.Binding{k.PrevMatch, k.NextMatch, withHelpDesc(k.Submit, "apply filter"), withHelpDesc(k.Cancel, "clear")}
+	}
+
+	bindings := []key.Binding{k.Up, k.Down, k.Toggle, k.Add, k.Delete, k.Heatmap, k.Search}
+	if m.search != "" {
+		bindings = append(bindings, k.ClearSearch)
+	}
+	return append(bindings, k.Help, k.Quit)
+}
+
+// fullHelp groups every list and heatmap binding for the help overlay.
+func (m *Model) fullHelp() [][]key.Binding {
+	k := m.keys
+	return [][]key.Binding{
+		{k.Up, k.Down, k.Top, k.Bottom, k.MoveUp, k.MoveDown},
+		{k.Toggle, k.Add, k.Delete, k.Heatmap, k.AllHabits, k.Calendar, k.Overview, k.Charts, k.Timing, k.Insights, k.Goals, k.Summary},
+		{k.Category, k.Tags, k.TagFilter, k.Search, k.ClearSearch, k.Sort},
+		{k.WeeksLess, k.WeeksMore, k.PrevDay, k.NextDay, k.PrevMonth, k.NextMonth, k.Today, k.Note, k.Value, k.Range, k.PrevChart, k.NextChart},
+		{k.Back, k.Help, k.Quit},
+	}
+}
+
+func withHelpDesc(b key.Binding, desc string) key.Binding {
+	b.SetHelp(b.Help().Key, desc)
+	return b
+}
+
+// renderHelp renders the footer for the current mode, wrapped to the
+// window width.
+func (m *Model) renderHelp() string {
+	var hints []string
+	for _, b := range m.shortHelp() {
+		if b.Enabled() {
+			hints = append(hints, b.Help().Key+": "+b.Help().Desc)
+		}
+	}
+	return m.helpLines(hints...)
+}
+
+func newHelpModel() help.Model {
+	h := help.New()
+	h.ShowAll = true
+	h.FullSeparator = "    "
+	h.Styles.FullKey = lipgloss.NewStyle().Foreground(theme.Accent).Bold(true)
+	h.Styles.FullDesc = lipgloss.NewStyle().Foreground(theme.Text)
+	h.Styles.FullSeparator = dimStyle
+	h.Styles.ShortKey = h.Styles.FullKey
+	h.Styles.ShortDesc = h.Styles.FullDesc
+	h.Styles.ShortSeparator = dimStyle
+	h.Styles.Ellipsis = dimStyle
+	return h
+}
+
+// showHelp opens the help overlay over the current screen.
+func (m *Model) showHelp() {
+	m.prevMode = m.mode
+	m.mode = modeHelp
+}
+
+func (m *Model) updateHelp(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	if key.Matches(msg, m.keys.Back, m.keys.Help) {
+		m.mode = m.prevMode
+	}
+	return m, nil
+}
+
+// viewHelp is the full-screen overlay listing every binding.
+func (m *Model) viewHelp() string {
+	var s strings.Builder
+
+	s.WriteString(titleStyle.Render("Keyboard Shortcuts") + "\n\n")
+
+	width := m.contentWidth()
+	m.help.Width = width
+	s.WriteString(m.help.FullHelpView(m.fullHelp()) + "\n\n")
+
+	note := "Rebind keys with the \"keys\" object in config.json, e.g. {\"toggle\": [\"x\", \"enter\"]}. " +
+		"Actions: " + strings.Join(m.keys.actionNames(), ", ") + "."
+	s.WriteString(dimStyle.Render(ansi.Wrap(note, width, "")) + "\n\n")
+	s.WriteString(m.helpLines(m.keys.Back.Help().Key + "/" + m.keys.Help.Help().Key + ": close"))
+
+	return s.String()
+}
+
+func (k *keyMap) actionNames() []string {
+	var names []string
+	for name := range k.named() {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
@@ -0,0 +1,60 @@
+package main
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestLoadKeyMapConflicts(t *testing.T) {
+	tests := []struct {
+		overrides map[string][]string
+		wantErr   string // part of the error, or empty for none
+	}{
+		{overrides: nil},
+		{overrides: map[string][]string{"help": {"s"}}, wantErr: "list screen"},
+		{overrides: map[string][]string{"weeks_less": {"?"}}, wantErr: "heatmap screen"},
+		{overrides: map[string][]string{"back": {"x"}, "summary": {"x"}}, wantErr: "summary screen"},
+		{overrides: map[string][]string{"back": {"x"}, "help": {"x"}}, wantErr: "both back and help"},
+		// Unbinding an action leaves its keys free
+		{overrides: map[string][]string{"up": {}, "summary": {"k"}}},
+		{overrides: map[string][]string{"nope": {"x"}}, wantErr: "unknown action"},
+	}
+
+	for _, tt := range tests {
+		_, err := loadKeyMap(tt.overrides)
+		if tt.wantErr == "" {
+			if err != nil {
+				t.Errorf("loadKeyMap(%v): %v", tt.overrides, err)
+			}
+			continue
+		}
+		if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+			t.Errorf("loadKeyMap(%v) error = %v, want one mentioning %q", tt.overrides, err, tt.wantErr)
+		}
+	}
+}
+
+func TestHintsNameReboundKeys(t *testing.T) {
+	m := newTestModel(t)
+	keys, err := loadKeyMap(map[string][]string{"tags": {"ctrl+t"}, "add": {"+"}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	m.keys = keys
+
+	m.toggleSelected()
+	if !strings.Contains(m.message, "Press '+'") {
+		t.Errorf("empty list hint = %q, want it to name +", m.message)
+	}
+
+	if err := m.db.AddHabit("Read"); err != nil {
+		t.Fatal(err)
+	}
+	if err := m.refresh(); err != nil {
+		t.Fatal(err)
+	}
+	m.cycleTagFilter()
+	if !strings.Contains(m.message, "Press 'ctrl+t'") {
+		t.Errorf("tag filter hint = %q, want it to name ctrl+t", m.message)
+	}
+}
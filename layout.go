@@ -0,0 +1,150 @@
+package main
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/charmbracelet/lipgloss"
+)
+
+// ============================================================
+// LAYOUT
+// ============================================================
+
+// Width of the stats and recent check-in boxes in the heatmap view, and
+// the gap between them when they sit side by side.
+const (
+	panelWidth = 50
+	panelGap   = 2
+)
+
+// contentWidth is the room inside the outer box, or 0 before the first
+// WindowSizeMsg arrives.
+func (m *Model) contentWidth() int {
+	if m.width == 0 {
+		return 0
+	}
+	return max(m.width-boxStyle.GetHorizontalFrameSize(), 0)
+}
+
+// scrollList shows the part of lines that fits in height rows, scrolling
+// just enough to keep lines[start:end] (the cursor row) visible. A height
+// of 0 or less means unlimited. It also returns, for each output line, the
+// index into lines shown there, or -1 for the scroll indicators.
+func (m *Model) scrollList(lines []string, start, end, height int) (string, []int) {
+	if height <= 0 || len(lines) <= height {
+		m.offset = 0
+		shown := make([]int, len(lines))
+		for i := range shown {
+			shown[i] = i
+		}
+		return strings.Join(lines, "\n"), shown
+	}
+
+	// Leave a line above and below for the scroll indicators
+	view := max(height-2, 1)
+	if start < m.offset {
+		m.offset = start
+	}
+	if end > m.offset+view {
+		m.offset = end - view
+	}
+	m.offset = max(min(m.offset, len(lines)-view), 0)
+
+	shown := []int{-1}
+	for i := m.offset; i < m.offset+view; i++ {
+		shown = append(shown, i)
+	}
+	shown = append(shown, -1)
+
+	var b strings.Builder
+	if m.offset > 0 {
+		b.WriteString(dimStyle.Render(fmt.Sprintf("  %s %d more", glyph("↑", "^"), m.offset)))
+	}
+	b.WriteString("\n")
+	b.WriteString(strings.Join(lines[m.offset:m.offset+view], "\n"))
+	b.WriteString("\n")
+	if below := len(lines) - m.offset - view; below > 0 {
+		b.WriteString(dimStyle.Render(fmt.Sprintf("  %s %d more", glyph("↓", "v"), below)))
+	}
+	return b.String(), shown
+}
+
+// heatmapWeeks is the number of weeks the heatmap shows: the chosen range,
+// cut down to what fits the terminal width.
+func (m *Model) heatmapWeeks() int {
+	width := m.contentWidth()
+	if width == 0 {
+		return m.weeks
+	}
+
+	// Each week is a 4 column cell after the 8 column day labels, inside
+	// the heatmap box's border and padding. The grid can have one more
+	// column than the week count when the range does not start on a Sunday.
+	fit := (width-heatmapBoxStyle.GetHorizontalFrameSize()-8)/4 - 1
+	return max(min(m.weeks, fit), minWeeks)
+}
+
+// joinPanels puts the boxes side by side when they fit the width, and
+// stacks them otherwise.
+func (m *Model) joinPanels(panels ...string) string {
+	width := m.contentWidth()
+	total := 0
+	for _, p := range panels {
+		total += lipgloss.Width(p)
+	}
+	total += panelGap * (len(panels) - 1)
+
+	if width == 0 || total > width {
+		return strings.Join(panels, "\n\n")
+	}
+
+	row := []string{panels[0]}
+	for _, p := range panels[1:] {
+		row = append(row, strings.Repeat(" ", panelGap), p)
+	}
+	return lipgloss.JoinHorizontal(lipgloss.Top, row...)
+}
+
+// panelStyle is a rounded box sized to panelWidth, or narrower when the
+// terminal is.
+func (m *Model) panelStyle() lipgloss.Style {
+	style := lipgloss.NewStyle().
+		Border(roundedBorder()).
+		BorderForeground(theme.Accent).
+		Padding(1, 2).
+		Width(panelWidth)
+
+	if width := m.contentWidth(); width > 0 && width < panelWidth+style.GetHorizontalBorderSize() {
+		style = style.Width(max(width-style.GetHorizontalBorderSize(), 20))
+	}
+	return style
+}
+
+// helpLines joins key hints with " | ", wrapping onto new lines instead of
+// running past the window.
+func (m *Model) helpLines(hints ...string) string {
+	width := m.contentWidth()
+
+	var lines []string
+	line := ""
+	for _, hint := range hints {
+		switch {
+		case line == "":
+			line = hint
+		case width > 0 && lipgloss.Width(line+" | "+hint) > width:
+			lines = append(lines, line)
+			line = hint
+		default:
+			line += " | " + hint
+		}
+	}
+	if line != "" {
+		lines = append(lines, line)
+	}
+
+	for i := range lines {
+		lines[i] = dimStyle.Render(lines[i])
+	}
+	return strings.Join(lines, "\n")
+}
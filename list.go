@@ -0,0 +1,225 @@
+package main
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+
+	"github.com/charmbracelet/lipgloss"
+)
+
+// ============================================================
+// LIST LAYOUT
+// ============================================================
+
+// listRow is one line of the habit list: either a category header or a
+// habit (an index into Model.habits plus its ID, which stays valid across
+// reloads).
+type listRow struct {
+	header   bool
+	category int // category ID, 0 for uncategorized
+	habit    int
+	id       int
+}
+
+// selected returns the habit under the cursor, if the cursor is on a habit.
+func (m *Model) selected() (Habit, bool) {
+	if m.cursor < 0 || m.cursor >= len(m.rows) || m.rows[m.cursor].header {
+		return Habit{}, false
+	}
+	return m.habits[m.rows[m.cursor].habit], true
+}
+
+// grouped reports whether any habit has a category; an entirely
+// uncategorized list is shown flat, without headers.
+func (m *Model) grouped() bool {
+	for _, h := range m.habits {
+		if h.CategoryID != 0 {
+			return true
+		}
+	}
+	return false
+}
+
+func (m *Model) category(id int) Category {
+	for _, c := range m.categories {
+		if c.ID == id {
+			return c
+		}
+	}
+	return Category{Name: "Uncategorized", Color: "#626262", Icon: "📂"}
+}
+
+// categoryName returns the name of a category, or "" for none.
+func (m *Model) categoryName(id int) string {
+	if id == 0 {
+		return ""
+	}
+	return m.category(id).Name
+}
+
+// visible reports whether a habit passes the active tag filter and search.
+func (m *Model) visible(h Habit) bool {
+	if m.tagFilter != "" && !slices.Contains(h.Tags, m.tagFilter) {
+		return false
+	}
+	_, ok := m.searchMatch(h)
+	return ok
+}
+
+// rebuildRows lays the habits out in groups, keeping the cursor on the same
+// habit or header where possible.
+func (m *Model) rebuildRows() {
+	var keep listRow
+	if m.cursor >= 0 && m.cursor < len(m.rows) {
+		keep = m.rows[m.cursor]
+	}
+
+	m.rows = m.rows[:0]
+	if !m.grouped() {
+		for i, h := range m.habits {
+			if m.visible(h) {
+				m.rows = append(m.rows, listRow{habit: i, id: h.ID})
+			}
+		}
+	} else {
+		// Categories in name order, uncategorized last
+		order := make([]int, 0, len(m.categories)+1)
+		for _, c := range m.categories {
+			order = append(order, c.ID)
+		}
+		order = append(order, 0)
+
+		for _, catID := range order {
+			var members []int
+			for i, h := range m.habits {
+				if h.CategoryID == catID && m.visible(h) {
+					members = append(members, i)
+				}
+			}
+			if len(members) == 0 {
+				continue
+			}
+
+			m.rows = append(m.rows, listRow{header: true, category: catID})
+			// Search results are never hidden in a collapsed group
+			if m.collapsed[catID] && m.search == "" {
+				continue
+			}
+			for _, i := range members {
+				m.rows = append(m.rows, listRow{category: catID, habit: i, id: m.habits[i].ID})
+			}
+		}
+	}
+
+	// Restore the cursor
+	for i, row := range m.rows {
+		sameHeader := keep.header && row.header && row.category == keep.category
+		sameHabit := !keep.header && !row.header && row.id == keep.id
+		if sameHeader || sameHabit {
+			m.cursor = i
+			return
+		}
+	}
+	if m.cursor >= len(m.rows) {
+		m.cursor = len(m.rows) - 1
+	}
+	if m.cursor < 0 {
+		m.cursor = 0
+	}
+}
+
+// selectHabit moves the cursor to the habit with the given ID.
+func (m *Model) selectHabit(id int) {
+	for i, row := range m.rows {
+		if !row.header && row.id == id {
+			m.cursor = i
+			return
+		}
+	}
+}
+
+// allTags returns every tag in use, sorted.
+func (m *Model) allTags() []string {
+	var tags []string
+	for _, h := range m.habits {
+		for _, t := range h.Tags {
+			if !slices.Contains(tags, t) {
+				tags = append(tags, t)
+			}
+		}
+	}
+	slices.Sort(tags)
+	return tags
+}
+
+// cycleTagFilter steps the tag filter through all tags and back to none.
+func (m *Model) cycleTagFilter() {
+	tags := m.allTags()
+	if len(tags) == 0 {
+		m.tagFilter = ""
+		m.setMessage("No tags yet. Press '"+m.keys.Tags.Help().Key+"' to tag a habit", "info")
+		return
+	}
+
+	next := 0
+	if i := slices.Index(tags, m.tagFilter); i >= 0 {
+		next = i + 1
+	}
+	if next >= len(tags) {
+		m.tagFilter = ""
+	} else {
+		m.tagFilter = tags[next]
+	}
+	m.rebuildRows()
+}
+
+// renderCategoryHeader shows a group's aggregate stats: habits done today,
+// average current streak and total completions.
+func (m *Model) renderCategoryHeader(catID int, selected bool) string {
+	c := m.category(catID)
+
+	total, done, streaks, completions := 0, 0, 0, 0
+	for _, h := range m.habits {
+		if h.CategoryID != catID || !m.visible(h) {
+			continue
+		}
+		total++
+		if m.doneToday[h.ID] {
+			done++
+		}
+		streaks += h.CurrentStreak
+		completions += h.TotalDone
+	}
+
+	arrow := glyph("▾", "v")
+	if m.collapsed[catID] && m.search == "" {
+		arrow = glyph("▸", ">")
+	}
+
+	avgStreak := 0.0
+	if total > 0 {
+		avgStreak = float64(streaks) / float64(total)
+	}
+
+	title := fmt.Sprintf("%s %s%s", arrow, glyph(c.Icon+" ", ""), c.Name)
+	if selected && (noColor() || asciiMode) {
+		// Without colors the background highlight is lost
+		title = glyph("› ", "> ") + title
+	}
+	stats := fmt.Sprintf(glyph("  %d/%d today · avg 🔥 %.1f · %d total", "  %d/%d today | avg streak %.1f | %d total"),
+		done, total, avgStreak, completions)
+
+	style := lipgloss.NewStyle().Bold(true).Foreground(lipgloss.Color(c.Color))
+	if selected {
+		style = style.Background(theme.Selection)
+	}
+	return style.Render(title) + dimStyle.Render(stats)
+}
+
+func renderTags(tags []string) string {
+	if len(tags) == 0 {
+		return ""
+	}
+	return " " + dimStyle.Render("#"+strings.Join(tags, " #"))
+}
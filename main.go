@@ -2,11 +2,18 @@ package main
 
 import (
 	"database/sql"
+	"errors"
+	"flag"
 	"fmt"
+	"math"
 	"os"
+	"slices"
+	"strconv"
 	"strings"
 	"time"
 
+	"github.com/charmbracelet/bubbles/help"
+	"github.com/charmbracelet/bubbles/key"
 	"github.com/charmbracelet/bubbles/textinput"
 	tea "github.com/charmbracelet/bubbletea"
 	"github.com/charmbracelet/lipgloss"
@@ -33,7 +40,8 @@ const (
 // ============================================================
 
 type Database struct {
-	db *sql.DB
+	db        *sql.DB
+	listeners []func(Event)
 }
 
 type Habit struct {
@@ -45,15 +53,27 @@ type Habit struct {
 	Level         int
 	XP            int
 	Coins         int
+	Strength      float64  // 0-100, see strengthHistory
+	StrengthTrend float64  // change in strength over the last week
+	CategoryID    int      // 0 when uncategorized
+	Tags          []string // only loaded by GetHabits
 }
 
 type LogEntry struct {
 	Date      string
 	Timestamp string
+	Note      string
+	Value     *float64 // nil when not recorded
 }
 
+// schemaVersion is stored in the database once the schema and migrations
+// below have been applied. Bump it whenever they change.
+const schemaVersion = 1
+
 func NewDatabase() (*Database, error) {
-	db, err := sql.Open("sqlite", "./habits.db")
+	// busy_timeout lets background writers (e.g. webhook delivery logging)
+	// wait for the lock instead of failing with SQLITE_BUSY.
+	db, err := sql.Open("sqlite", "file:habits.db?_pragma=busy_timeout(5000)")
 	if err != nil {
 		return nil, fmt.Errorf("failed to open database: %w", err)
 	}
@@ -64,6 +84,17 @@ func NewDatabase() (*Database, error) {
 		return nil, fmt.Errorf("failed to connect to database: %w", err)
 	}
 
+	// An up-to-date database is opened without writing to it, which keeps
+	// commands run on every prompt, like status, fast
+	var version int
+	if err := db.QueryRow("PRAGMA user_version").Scan(&version); err != nil {
+		db.Close()
+		return nil, fmt.Errorf("failed to read schema version: %w", err)
+	}
+	if version == schemaVersion {
+		return &Database{db: db}, nil
+	}
+
 	schema := `
 		CREATE TABLE IF NOT EXISTS habits (
 			id INTEGER PRIMARY KEY AUTOINCREMENT,
@@ -93,6 +124,67 @@ func NewDatabase() (*Database, error) {
 			FOREIGN KEY (habit_id) REFERENCES habits(id) ON DELETE CASCADE
 		);
 
+		CREATE TABLE IF NOT EXISTS global_achievements (
+			type TEXT PRIMARY KEY,
+			unlocked_at TEXT DEFAULT CURRENT_TIMESTAMP
+		);
+
+		CREATE TABLE IF NOT EXISTS webhook_deliveries (
+			id INTEGER PRIMARY KEY AUTOINCREMENT,
+			url TEXT NOT NULL,
+			event TEXT NOT NULL,
+			payload TEXT NOT NULL,
+			attempt INTEGER NOT NULL,
+			status_code INTEGER,
+			error TEXT,
+			delivered_at TEXT DEFAULT CURRENT_TIMESTAMP
+		);
+
+		CREATE TABLE IF NOT EXISTS reminders (
+			habit_id INTEGER PRIMARY KEY,
+			time TEXT NOT NULL,
+			snoozed_until TEXT,
+			last_notified TEXT,
+			FOREIGN KEY (habit_id) REFERENCES habits(id) ON DELETE CASCADE
+		);
+
+		CREATE TABLE IF NOT EXISTS streak_alerts (
+			habit_id INTEGER PRIMARY KEY,
+			date TEXT NOT NULL,
+			FOREIGN KEY (habit_id) REFERENCES habits(id) ON DELETE CASCADE
+		);
+
+		CREATE TABLE IF NOT EXISTS settings (
+			key TEXT PRIMARY KEY,
+			value TEXT NOT NULL
+		);
+
+		CREATE TABLE IF NOT EXISTS categories (
+			id INTEGER PRIMARY KEY AUTOINCREMENT,
+			name TEXT NOT NULL UNIQUE COLLATE NOCASE CHECK(length(trim(name)) > 0),
+			color TEXT NOT NULL,
+			icon TEXT NOT NULL
+		);
+
+		CREATE TABLE IF NOT EXISTS goals (
+			id INTEGER PRIMARY KEY AUTOINCREMENT,
+			habit_id INTEGER NOT NULL,
+			kind TEXT NOT NULL CHECK(kind IN ('total', 'streak', 'rate')),
+			target INTEGER NOT NULL CHECK(target > 0),
+			start TEXT NOT NULL,
+			deadline TEXT,
+			met_at TEXT,
+			alerted TEXT,
+			FOREIGN KEY (habit_id) REFERENCES habits(id) ON DELETE CASCADE
+		);
+
+		CREATE TABLE IF NOT EXISTS habit_tags (
+			habit_id INTEGER NOT NULL,
+			tag TEXT NOT NULL,
+			PRIMARY KEY (habit_id, tag),
+			FOREIGN KEY (habit_id) REFERENCES habits(id) ON DELETE CASCADE
+		);
+
 		CREATE INDEX IF NOT EXISTS idx_logs_habit_date ON logs(habit_id, date);
 		CREATE INDEX IF NOT EXISTS idx_logs_date ON logs(date);
 	`
@@ -102,9 +194,69 @@ func NewDatabase() (*Database, error) {
 		return nil, fmt.Errorf("failed to create schema: %w", err)
 	}
 
+	if err := migrate(db); err != nil {
+		db.Close()
+		return nil, fmt.Errorf("failed to migrate schema: %w", err)
+	}
+
+	if _, err := db.Exec(fmt.Sprintf("PRAGMA user_version = %d", schemaVersion)); err != nil {
+		db.Close()
+		return nil, fmt.Errorf("failed to set schema version: %w", err)
+	}
+
 	return &Database{db: db}, nil
 }
 
+// migrate brings databases created by older versions up to date.
+func migrate(db *sql.DB) error {
+	if err := addColumn(db, "habits", "category_id", "INTEGER REFERENCES categories(id) ON DELETE SET NULL"); err != nil {
+		return err
+	}
+
+	if err := addColumn(db, "habits", "position", "INTEGER"); err != nil {
+		return err
+	}
+	// Existing habits keep their creation order
+	if _, err := db.Exec("UPDATE habits SET position = id WHERE position IS NULL"); err != nil {
+		return fmt.Errorf("failed to backfill positions: %w", err)
+	}
+
+	// Per-day annotations on check-ins
+	if err := addColumn(db, "logs", "note", "TEXT NOT NULL DEFAULT ''"); err != nil {
+		return err
+	}
+	if err := addColumn(db, "logs", "value", "REAL"); err != nil {
+		return err
+	}
+
+	// Habit strength, filled in by RefreshStrength and on every toggle
+	if err := addColumn(db, "habits", "strength", "REAL NOT NULL DEFAULT 0"); err != nil {
+		return err
+	}
+	if err := addColumn(db, "habits", "strength_trend", "REAL NOT NULL DEFAULT 0"); err != nil {
+		return err
+	}
+
+	return seedCategories(db)
+}
+
+// addColumn adds a column to an existing table unless it is already there.
+func addColumn(db *sql.DB, table, column, definition string) error {
+	var count int
+	err := db.QueryRow("SELECT COUNT(*) FROM pragma_table_info(?) WHERE name = ?", table, column).Scan(&count)
+	if err != nil {
+		return fmt.Errorf("failed to inspect %s: %w", table, err)
+	}
+	if count > 0 {
+		return nil
+	}
+
+	if _, err := db.Exec(fmt.Sprintf("ALTER TABLE %s ADD COLUMN %s %s", table, column, definition)); err != nil {
+		return fmt.Errorf("failed to add %s.%s: %w", table, column, err)
+	}
+	return nil
+}
+
 func (d *Database) Close() error {
 	if d.db != nil {
 		return d.db.Close()
@@ -123,7 +275,10 @@ func (d *Database) AddHabit(name string) error {
 		return fmt.Errorf("habit name too long (max %d characters)", maxHabitName)
 	}
 
-	_, err := d.db.Exec("INSERT INTO habits (name) VALUES (?)", name)
+	_, err := d.db.Exec(`
+		INSERT INTO habits (name, position)
+		VALUES (?, (SELECT COALESCE(MAX(position), 0) + 1 FROM habits))
+	`, name)
 	if err != nil {
 		return fmt.Errorf("failed to add habit: %w", err)
 	}
@@ -134,8 +289,9 @@ func (d *Database) AddHabit(name string) error {
 func (d *Database) GetHabits() ([]Habit, error) {
 	rows, err := d.db.Query(`
 		SELECT id, name, current_streak, total_done, 
-		       COALESCE(level, 1), COALESCE(xp, 0), COALESCE(coins, 0), created_at 
-		FROM habits ORDER BY id
+		       COALESCE(level, 1), COALESCE(xp, 0), COALESCE(coins, 0), created_at,
+		       COALESCE(category_id, 0), strength, strength_trend
+		FROM habits ORDER BY position, id
 	`)
 	if err != nil {
 		return nil, fmt.Errorf("failed to get habits: %w", err)
@@ -146,7 +302,7 @@ func (d *Database) GetHabits() ([]Habit, error) {
 	for rows.Next() {
 		var h Habit
 		if err := rows.Scan(&h.ID, &h.Name, &h.CurrentStreak, &h.TotalDone,
-			&h.Level, &h.XP, &h.Coins, &h.CreatedAt); err != nil {
+			&h.Level, &h.XP, &h.Coins, &h.CreatedAt, &h.CategoryID, &h.Strength, &h.StrengthTrend); err != nil {
 			return nil, fmt.Errorf("failed to scan habit: %w", err)
 		}
 		habits = append(habits, h)
@@ -156,6 +312,14 @@ func (d *Database) GetHabits() ([]Habit, error) {
 		return nil, fmt.Errorf("error iterating habits: %w", err)
 	}
 
+	tags, err := d.getTags()
+	if err != nil {
+		return nil, err
+	}
+	for i := range habits {
+		habits[i].Tags = tags[habits[i].ID]
+	}
+
 	return habits, nil
 }
 
@@ -196,6 +360,11 @@ func (d *Database) ToggleHabit(habitID int, date string) (bool, error) {
 	}
 	defer tx.Rollback()
 
+	before, err := scanHabit(tx, habitID)
+	if err != nil {
+		return false, err
+	}
+
 	isDone := false
 	if count > 0 {
 		// Remove log
@@ -218,10 +387,28 @@ func (d *Database) ToggleHabit(habitID int, date string) (bool, error) {
 		return false, fmt.Errorf("failed to recalculate stats: %w", err)
 	}
 
+	after, err := scanHabit(tx, habitID)
+	if err != nil {
+		return false, err
+	}
+
+	unlocked, err := recordAchievements(tx)
+	if err != nil {
+		return false, fmt.Errorf("failed to record achievements: %w", err)
+	}
+
+	met, err := recordGoals(tx, after)
+	if err != nil {
+		return false, fmt.Errorf("failed to record goals: %w", err)
+	}
+
 	if err := tx.Commit(); err != nil {
 		return false, fmt.Errorf("failed to commit transaction: %w", err)
 	}
 
+	events := append(habitEvents(before, after, date, isDone), achievementEvents(unlocked)...)
+	d.emit(append(events, goalEvents(after, met)...))
+
 	return isDone, nil
 }
 
@@ -249,10 +436,40 @@ func (d *Database) recalculateStats(tx *sql.Tx, habitID int) error {
 		return err
 	}
 
+	stats, err := computeStats(dates, time.Now())
+	if err != nil {
+		return err
+	}
+
+	_, err = tx.Exec(`
+		UPDATE habits 
+		SET current_streak = ?, total_done = ?, level = ?, xp = ?, coins = ?,
+		    strength = ?, strength_trend = ?
+		WHERE id = ?
+	`, stats.Streak, stats.TotalDone, stats.Level, stats.XP, stats.Coins,
+		stats.Strength, stats.StrengthTrend, habitID)
+
+	return err
+}
+
+type habitStats struct {
+	Streak    int
+	TotalDone int
+	Level     int
+	XP        int
+	Coins     int
+
+	Strength      float64
+	StrengthTrend float64
+}
+
+// computeStats derives streak, XP, level, coins and strength from a habit's log dates
+// (newest first) as of now.
+func computeStats(dates []string, now time.Time) (habitStats, error) {
 	// Calculate current streak
 	streak := 0
-	today := time.Now().Format("2006-01-02")
-	yesterday := time.Now().AddDate(0, 0, -1).Format("2006-01-02")
+	today := now.Format("2006-01-02")
+	yesterday := now.AddDate(0, 0, -1).Format("2006-01-02")
 
 	for i, dateStr := range dates {
 		if i == 0 {
@@ -266,12 +483,12 @@ func (d *Database) recalculateStats(tx *sql.Tx, habitID int) error {
 
 		prevDate, err := time.Parse("2006-01-02", dates[i-1])
 		if err != nil {
-			return fmt.Errorf("failed to parse previous date: %w", err)
+			return habitStats{}, fmt.Errorf("failed to parse previous date: %w", err)
 		}
 
 		currDate, err := time.Parse("2006-01-02", dateStr)
 		if err != nil {
-			return fmt.Errorf("failed to parse current date: %w", err)
+			return habitStats{}, fmt.Errorf("failed to parse current date: %w", err)
 		}
 
 		diff := int(prevDate.Sub(currDate).Hours() / 24)
@@ -300,13 +517,17 @@ func (d *Database) recalculateStats(tx *sql.Tx, habitID int) error {
 		xp += 1000 // Epic streak bonus
 	}
 
-	_, err = tx.Exec(`
-		UPDATE habits 
-		SET current_streak = ?, total_done = ?, level = ?, xp = ?, coins = ?
-		WHERE id = ?
-	`, streak, totalDone, level, xp, coins, habitID)
+	strength, trend := habitStrength(dates, now)
 
-	return err
+	return habitStats{
+		Streak:        streak,
+		TotalDone:     totalDone,
+		Level:         level,
+		XP:            xp,
+		Coins:         coins,
+		Strength:      strength,
+		StrengthTrend: trend,
+	}, nil
 }
 
 func (d *Database) GetLogs(habitID int, days int) (map[string]bool, error) {
@@ -346,7 +567,7 @@ func (d *Database) GetLogsWithTime(habitID int, days int) (map[string]LogEntry,
 	}
 
 	rows, err := d.db.Query(`
-		SELECT date, timestamp FROM logs 
+		SELECT date, timestamp, note, value FROM logs
 		WHERE habit_id = ?
 		AND date >= date('now', '-' || ? || ' days')
 		ORDER BY date DESC
@@ -359,7 +580,7 @@ func (d *Database) GetLogsWithTime(habitID int, days int) (map[string]LogEntry,
 	logs := make(map[string]LogEntry)
 	for rows.Next() {
 		var entry LogEntry
-		if err := rows.Scan(&entry.Date, &entry.Timestamp); err != nil {
+		if err := rows.Scan(&entry.Date, &entry.Timestamp, &entry.Note, &entry.Value); err != nil {
 			return nil, fmt.Errorf("failed to scan log entry: %w", err)
 		}
 		logs[entry.Date] = entry
@@ -372,32 +593,165 @@ func (d *Database) GetLogsWithTime(habitID int, days int) (map[string]LogEntry,
 	return logs, nil
 }
 
+// GetDoneOn returns the IDs of all habits logged on the given date.
+func (d *Database) GetDoneOn(date string) (map[int]bool, error) {
+	rows, err := d.db.Query("SELECT habit_id FROM logs WHERE date = ?", date)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get logs for %s: %w", date, err)
+	}
+	defer rows.Close()
+
+	done := make(map[int]bool)
+	for rows.Next() {
+		var id int
+		if err := rows.Scan(&id); err != nil {
+			return nil, fmt.Errorf("failed to scan log: %w", err)
+		}
+		done[id] = true
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating logs: %w", err)
+	}
+
+	return done, nil
+}
+
+// SwapHabits exchanges the manual list positions of two habits.
+func (d *Database) SwapHabits(a, b int) error {
+	tx, err := d.db.Begin()
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	var posA, posB int
+	if err := tx.QueryRow("SELECT position FROM habits WHERE id = ?", a).Scan(&posA); err != nil {
+		return fmt.Errorf("failed to get position: %w", err)
+	}
+	if err := tx.QueryRow("SELECT position FROM habits WHERE id = ?", b).Scan(&posB); err != nil {
+		return fmt.Errorf("failed to get position: %w", err)
+	}
+
+	if _, err := tx.Exec("UPDATE habits SET position = ? WHERE id = ?", posB, a); err != nil {
+		return fmt.Errorf("failed to reorder habits: %w", err)
+	}
+	if _, err := tx.Exec("UPDATE habits SET position = ? WHERE id = ?", posA, b); err != nil {
+		return fmt.Errorf("failed to reorder habits: %w", err)
+	}
+
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("failed to commit transaction: %w", err)
+	}
+	return nil
+}
+
+// GetSetting returns a stored UI setting, or def if it was never set.
+func (d *Database) GetSetting(key, def string) (string, error) {
+	var value string
+	err := d.db.QueryRow("SELECT value FROM settings WHERE key = ?", key).Scan(&value)
+	if err == sql.ErrNoRows {
+		return def, nil
+	}
+	if err != nil {
+		return "", fmt.Errorf("failed to get setting %s: %w", key, err)
+	}
+	return value, nil
+}
+
+func (d *Database) SetSetting(key, value string) error {
+	_, err := d.db.Exec(`
+		INSERT INTO settings (key, value) VALUES (?, ?)
+		ON CONFLICT(key) DO UPDATE SET value = excluded.value
+	`, key, value)
+	if err != nil {
+		return fmt.Errorf("failed to save setting %s: %w", key, err)
+	}
+	return nil
+}
+
+// GetHabit returns a single habit by ID.
+func (d *Database) GetHabit(id int) (Habit, error) {
+	return scanHabit(d.db, id)
+}
+
+// FindHabit looks a habit up by ID or by case-insensitive name.
+func (d *Database) FindHabit(ref string) (Habit, error) {
+	if id, err := strconv.Atoi(ref); err == nil {
+		return d.GetHabit(id)
+	}
+
+	habits, err := d.GetHabits()
+	if err != nil {
+		return Habit{}, err
+	}
+
+	var match []Habit
+	for _, h := range habits {
+		if strings.EqualFold(h.Name, strings.TrimSpace(ref)) {
+			match = append(match, h)
+		}
+	}
+
+	switch len(match) {
+	case 0:
+		return Habit{}, fmt.Errorf("no habit named %q", ref)
+	case 1:
+		return match[0], nil
+	default:
+		return Habit{}, fmt.Errorf("%d habits are named %q, use the ID instead", len(match), ref)
+	}
+}
+
+// rowQuerier is satisfied by both *sql.DB and *sql.Tx.
+type rowQuerier interface {
+	QueryRow(query string, args ...any) *sql.Row
+}
+
+func scanHabit(q rowQuerier, id int) (Habit, error) {
+	var h Habit
+	err := q.QueryRow(`
+		SELECT id, name, current_streak, total_done,
+		       COALESCE(level, 1), COALESCE(xp, 0), COALESCE(coins, 0), created_at,
+		       COALESCE(category_id, 0), strength, strength_trend
+		FROM habits WHERE id = ?
+	`, id).Scan(&h.ID, &h.Name, &h.CurrentStreak, &h.TotalDone,
+		&h.Level, &h.XP, &h.Coins, &h.CreatedAt, &h.CategoryID, &h.Strength, &h.StrengthTrend)
+	if err == sql.ErrNoRows {
+		return h, fmt.Errorf("habit not found")
+	}
+	if err != nil {
+		return h, fmt.Errorf("failed to get habit: %w", err)
+	}
+
+	return h, nil
+}
+
 // ============================================================
 // STYLES
 // ============================================================
 
+// Set from the active theme by applyTheme
 var (
-	titleStyle    = lipgloss.NewStyle().Bold(true).Foreground(lipgloss.Color("#7D56F4")).Padding(0, 1)
-	subtitleStyle = lipgloss.NewStyle().Foreground(lipgloss.Color("#7D56F4"))
-	selectedStyle = lipgloss.NewStyle().Bold(true).Foreground(lipgloss.Color("#7D56F4")).Background(lipgloss.Color("#3C3C3C")).Padding(0, 1)
-	normalStyle   = lipgloss.NewStyle().Foreground(lipgloss.Color("#FAFAFA"))
-	dimStyle      = lipgloss.NewStyle().Foreground(lipgloss.Color("#626262"))
-	successStyle  = lipgloss.NewStyle().Foreground(lipgloss.Color("#39D353")).Bold(true)
-	errorStyle    = lipgloss.NewStyle().Foreground(lipgloss.Color("#FF5F87")).Bold(true)
-	streakStyle   = lipgloss.NewStyle().Foreground(lipgloss.Color("#FFA500")).Bold(true)
-	warningStyle  = lipgloss.NewStyle().Foreground(lipgloss.Color("#FFA500"))
-
-	// Heatmap colors (GitHub-style)
-	colorNone   = lipgloss.Color("#161B22")
-	colorLevel1 = lipgloss.Color("#0E4429")
-	colorLevel2 = lipgloss.Color("#006D32")
-	colorLevel3 = lipgloss.Color("#26A641")
-	colorLevel4 = lipgloss.Color("#39D353")
-
-	boxStyle = lipgloss.NewStyle().
-			Border(lipgloss.RoundedBorder()).
-			BorderForeground(lipgloss.Color("#7D56F4")).
-			Padding(1, 2)
+	titleStyle    lipgloss.Style
+	subtitleStyle lipgloss.Style
+	selectedStyle lipgloss.Style
+	normalStyle   lipgloss.Style
+	dimStyle      lipgloss.Style
+	successStyle  lipgloss.Style
+	errorStyle    lipgloss.Style
+	streakStyle   lipgloss.Style
+	warningStyle  lipgloss.Style
+
+	// Heatmap colors (GitHub-style in the default theme)
+	colorNone   lipgloss.Color
+	colorLevel1 lipgloss.Color
+	colorLevel2 lipgloss.Color
+	colorLevel3 lipgloss.Color
+	colorLevel4 lipgloss.Color
+
+	boxStyle        lipgloss.Style
+	heatmapBoxStyle lipgloss.Style
 )
 
 // ============================================================
@@ -411,49 +765,119 @@ const (
 	modeAdd
 	modeDelete
 	modeHeatmap
+	modeCategory
+	modeTags
+	modeSearch
+	modeSummary
+	modeHelp
+	modeCalendar
+	modeNote
+	modeValue
+	modeMatrix
+	modeChart
+	modeTiming
+	modeInsights
+	modeGoals
+	modeGoalAdd
 )
 
 type Model struct {
 	db           *Database
 	habits       []Habit
+	categories   []Category
+	rows         []listRow
+	collapsed    map[int]bool // category ID -> collapsed
+	tagFilter    string
+	search       string
+	sortMode     sortMode
+	doneToday    map[int]bool
 	cursor       int
 	mode         mode
 	input        textinput.Model
+	keys         keyMap
+	help         help.Model
+	prevMode     mode // mode to return to when the help overlay closes
 	message      string
 	messageType  string // "success", "error", "info"
 	logs         map[string]bool
 	logsWithTime map[string]LogEntry
+	achievements []Unlocked
 	weeks        int
 	width        int
 	height       int
+	offset       int    // first visible list line
+	summaryTop   int    // first visible summary line
+	inspected    string // heatmap date last clicked
+	heatmapAll   bool   // heatmap shows all habits combined
+	dayTotals    map[string]int
+	habitStarts  map[int]string
+	calDay       time.Time // focused calendar day
+	matrixLogs   map[int]map[string]bool
+	matrixRange  int                     // index into matrixRanges
+	matrixCol    int                     // focused overview day, in days before today
+	recentLogs   map[int]map[string]bool // check-ins behind the list sparklines
+	chartDates   []string                // every check-in of the charted habit
+	chart        chartKind
+	timingLogs   map[string]LogEntry // check-ins behind the time of day view
+	insights     []insight           // strongest relationships first
+	insightRange int                 // index into insightRanges
+	goals        []Goal              // the selected habit's goals
+	goalDates    map[string]bool     // every check-in of the selected habit
+	goalCursor   int
+	goalsMet     []string  // goals met since the last key, to announce
+	hits         []hitZone // clickable areas of the last View
+	viewHeight   int       // lines in the last View
 	err          error
 }
 
-func NewModel() (*Model, error) {
+func NewModel(keys keyMap) (*Model, error) {
 	db, err := NewDatabase()
 	if err != nil {
 		return nil, err
 	}
-
-	habits, err := db.GetHabits()
-	if err != nil {
+	if err := db.RefreshStrength(); err != nil {
 		db.Close()
-		return nil, fmt.Errorf("failed to load habits: %w", err)
+		return nil, err
+	}
+	// main has loaded the configured achievements by now
+	if err := db.RecordAchievements(); err != nil {
+		db.Close()
+		return nil, err
 	}
 
 	input := textinput.New()
-	input.Placeholder = "Enter habit name..."
 	input.Width = 50
-	input.CharLimit = maxHabitName
 
-	return &Model{
+	order, err := db.GetSetting("sort", sortManual.String())
+	if err != nil {
+		db.Close()
+		return nil, err
+	}
+
+	m := &Model{
 		db:          db,
-		habits:      habits,
+		sortMode:    parseSortMode(order),
+		collapsed:   make(map[int]bool),
 		mode:        modeList,
 		input:       input,
+		keys:        keys,
+		help:        newHelpModel(),
 		weeks:       12,
 		messageType: "info",
-	}, nil
+	}
+
+	if err := m.refresh(); err != nil {
+		db.Close()
+		return nil, fmt.Errorf("failed to load habits: %w", err)
+	}
+
+	db.Subscribe(m.noteGoalsMet)
+	if err := m.warnGoalsAtRisk(); err != nil {
+		db.Close()
+		return nil, err
+	}
+
+	return m, nil
 }
 
 func (m *Model) Init() tea.Cmd {
@@ -467,7 +891,7 @@ func (m *Model) setMessage(msg string, msgType string) {
 
 func (m *Model) setError(err error) {
 	if err != nil {
-		m.setMessage("❌ "+err.Error(), "error")
+		m.setMessage(glyph("❌ ", "Error: ")+err.Error(), "error")
 		m.err = err
 	}
 }
@@ -481,6 +905,8 @@ func (m *Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 	case tea.WindowSizeMsg:
 		m.width = msg.Width
 		m.height = msg.Height
+		// A narrower window may show fewer overview days than the focus
+		m.matrixCol = min(m.matrixCol, m.matrixDays()-1)
 		return m, nil
 
 	case tea.KeyMsg:
@@ -488,16 +914,54 @@ func (m *Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 			return m, tea.Quit
 		}
 
-		switch m.mode {
-		case modeList:
-			return m.updateList(msg)
-		case modeAdd:
-			return m.updateAdd(msg)
-		case modeDelete:
-			return m.updateDelete(msg)
-		case modeHeatmap:
-			return m.updateHeatmap(msg)
-		}
+		model, cmd := m.updateKey(msg)
+		m.announceGoals()
+		return model, cmd
+
+	case tea.MouseMsg:
+		model, cmd := m.updateMouse(msg)
+		m.announceGoals()
+		return model, cmd
+	}
+
+	return m, nil
+}
+
+// updateKey hands a key to the current screen.
+func (m *Model) updateKey(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	switch m.mode {
+	case modeList:
+		return m.updateList(msg)
+	case modeAdd:
+		return m.updateAdd(msg)
+	case modeDelete:
+		return m.updateDelete(msg)
+	case modeHeatmap:
+		return m.updateHeatmap(msg)
+	case modeCategory, modeTags:
+		return m.updateEdit(msg)
+	case modeSearch:
+		return m.updateSearch(msg)
+	case modeSummary:
+		return m.updateSummary(msg)
+	case modeHelp:
+		return m.updateHelp(msg)
+	case modeCalendar:
+		return m.updateCalendar(msg)
+	case modeNote, modeValue:
+		return m.updateAnnotate(msg)
+	case modeMatrix:
+		return m.updateMatrix(msg)
+	case modeChart:
+		return m.updateChart(msg)
+	case modeTiming:
+		return m.updateTiming(msg)
+	case modeInsights:
+		return m.updateInsights(msg)
+	case modeGoals:
+		return m.updateGoals(msg)
+	case modeGoalAdd:
+		return m.updateGoalAdd(msg)
 	}
 
 	return m, nil
@@ -507,113 +971,226 @@ func (m *Model) updateList(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
 	m.message = ""
 	m.err = nil
 
-	switch msg.String() {
-	case "q":
+	switch {
+	case key.Matches(msg, m.keys.Quit):
 		return m, tea.Quit
 
-	case "up", "k":
+	case key.Matches(msg, m.keys.Up):
 		if m.cursor > 0 {
 			m.cursor--
 		}
 
-	case "down", "j":
-		if m.cursor < len(m.habits)-1 {
+	case key.Matches(msg, m.keys.Down):
+		if m.cursor < len(m.rows)-1 {
 			m.cursor++
 		}
 
-	case "g":
+	case key.Matches(msg, m.keys.Top):
 		m.cursor = 0
 
-	case "G":
-		if len(m.habits) > 0 {
-			m.cursor = len(m.habits) - 1
+	case key.Matches(msg, m.keys.Bottom):
+		if len(m.rows) > 0 {
+			m.cursor = len(m.rows) - 1
 		}
 
-	case "a":
+	case key.Matches(msg, m.keys.Add):
 		m.mode = modeAdd
+		m.input.Placeholder = "Enter habit name..."
+		m.input.CharLimit = maxHabitName
 		m.input.SetValue("")
 		m.input.Focus()
 
-	case "d":
-		if len(m.habits) > 0 {
+	case key.Matches(msg, m.keys.Delete):
+		if _, ok := m.selected(); ok {
 			m.mode = modeDelete
 		} else {
-			m.setMessage("No habits to delete", "info")
+			m.setMessage("No habit selected to delete", "info")
 		}
 
-	case "enter", " ":
-		if len(m.habits) == 0 {
-			m.setMessage("No habits yet. Press 'a' to add one!", "info")
+	case key.Matches(msg, m.keys.Toggle):
+		m.toggleSelected()
+
+	case key.Matches(msg, m.keys.Heatmap):
+		habit, ok := m.selected()
+		if !ok {
+			m.setMessage("No habit selected to view", "info")
 			break
 		}
 
-		today := time.Now().Format("2006-01-02")
-		isDone, err := m.db.ToggleHabit(m.habits[m.cursor].ID, today)
-		if err != nil {
+		if err := m.loadHeatmap(habit.ID); err != nil {
 			m.setError(err)
-		} else {
-			if err := m.refresh(); err != nil {
-				m.setError(err)
-			} else {
-				if isDone {
-					m.setMessage("✓ Marked as done!", "success")
-				} else {
-					m.setMessage("○ Unmarked", "info")
-				}
-			}
-		}
-
-	case "h":
-		if len(m.habits) == 0 {
-			m.setMessage("No habits to view", "info")
 			break
 		}
+		m.inspected = ""
+		m.heatmapAll = false
+		m.mode = modeHeatmap
 
-		logs, err := m.db.GetLogs(m.habits[m.cursor].ID, maxLogDays)
-		if err != nil {
-			m.setError(err)
+	case key.Matches(msg, m.keys.AllHabits):
+		m.openCombinedHeatmap()
+
+	case key.Matches(msg, m.keys.Calendar):
+		m.openCalendar()
+
+	case key.Matches(msg, m.keys.Overview):
+		m.openMatrix()
+
+	case key.Matches(msg, m.keys.Charts):
+		m.openCharts()
+
+	case key.Matches(msg, m.keys.Timing):
+		m.openTiming()
+
+	case key.Matches(msg, m.keys.Insights):
+		m.openInsights()
+
+	case key.Matches(msg, m.keys.Goals):
+		m.openGoals()
+
+	case key.Matches(msg, m.keys.Category):
+		habit, ok := m.selected()
+		if !ok {
+			m.setMessage("No habit selected", "info")
 			break
 		}
+		m.mode = modeCategory
+		m.input.Placeholder = "Category (empty for none)..."
+		m.input.CharLimit = maxCategoryName
+		m.input.SetValue(m.categoryName(habit.CategoryID))
+		m.input.Focus()
 
-		logsWithTime, err := m.db.GetLogsWithTime(m.habits[m.cursor].ID, maxLogDays)
-		if err != nil {
-			m.setError(err)
+	case key.Matches(msg, m.keys.Tags):
+		habit, ok := m.selected()
+		if !ok {
+			m.setMessage("No habit selected", "info")
 			break
 		}
+		m.mode = modeTags
+		m.input.Placeholder = "Tags, separated by spaces or commas..."
+		m.input.CharLimit = 200
+		m.input.SetValue(strings.Join(habit.Tags, " "))
+		m.input.Focus()
 
-		m.logs = logs
-		m.logsWithTime = logsWithTime
-		m.mode = modeHeatmap
+	case key.Matches(msg, m.keys.TagFilter):
+		m.cycleTagFilter()
+
+	case key.Matches(msg, m.keys.MoveUp):
+		m.moveSelected(-1)
+
+	case key.Matches(msg, m.keys.MoveDown):
+		m.moveSelected(1)
+
+	case key.Matches(msg, m.keys.Sort):
+		m.cycleSort()
+
+	case key.Matches(msg, m.keys.Search):
+		m.startSearch()
+
+	case key.Matches(msg, m.keys.Summary):
+		m.mode = modeSummary
+
+	case key.Matches(msg, m.keys.Help):
+		m.showHelp()
+
+	case key.Matches(msg, m.keys.ClearSearch):
+		if m.search != "" {
+			m.setSearch("")
+		}
 	}
 
 	return m, nil
 }
 
-func (m *Model) updateAdd(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
-	switch msg.String() {
-	case "esc":
-		m.mode = modeList
-		m.input.Blur()
-		return m, nil
+// toggleSelected marks the selected habit done or not done today, or folds
+// the selected category.
+func (m *Model) toggleSelected() {
+	if len(m.habits) == 0 {
+		m.setMessage("No habits yet. Press '"+m.keys.Add.Help().Key+"' to add one!", "info")
+		return
+	}
 
-	case "enter":
-		name := strings.TrimSpace(m.input.Value())
-		if name == "" {
-			m.setMessage("Habit name cannot be empty", "error")
-			return m, nil
-		}
+	if m.cursor < len(m.rows) && m.rows[m.cursor].header {
+		catID := m.rows[m.cursor].category
+		m.collapsed[catID] = !m.collapsed[catID]
+		m.rebuildRows()
+		return
+	}
 
-		if err := m.db.AddHabit(name); err != nil {
+	habit, ok := m.selected()
+	if !ok {
+		return
+	}
+
+	today := time.Now().Format("2006-01-02")
+	isDone, err := m.db.ToggleHabit(habit.ID, today)
+	if err != nil {
+		m.setError(err)
+	} else {
+		if err := m.refresh(); err != nil {
 			m.setError(err)
 		} else {
-			if err := m.refresh(); err != nil {
-				m.setError(err)
+			if isDone {
+				m.setMessage(glyph("✓ ", "")+"Marked as done!", "success")
 			} else {
-				m.setMessage("✓ Habit added!", "success")
-				// Move cursor to the new habit (last in list)
-				if len(m.habits) > 0 {
-					m.cursor = len(m.habits) - 1
+				m.setMessage(glyph("○ ", "")+"Unmarked", "info")
+			}
+		}
+	}
+}
+
+// loadHeatmap loads the check-in history shown in the heatmap.
+func (m *Model) loadHeatmap(habitID int) error {
+	logs, err := m.db.GetLogs(habitID, maxLogDays)
+	if err != nil {
+		return err
+	}
+
+	logsWithTime, err := m.db.GetLogsWithTime(habitID, maxLogDays)
+	if err != nil {
+		return err
+	}
+
+	achievements, err := m.db.GetAchievements(habitID)
+	if err != nil {
+		return err
+	}
+
+	m.logs = logs
+	m.logsWithTime = logsWithTime
+	m.achievements = achievements
+	return nil
+}
+
+func (m *Model) updateAdd(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	switch {
+	case key.Matches(msg, m.keys.Cancel):
+		m.mode = modeList
+		m.input.Blur()
+		return m, nil
+
+	case key.Matches(msg, m.keys.Submit):
+		name := strings.TrimSpace(m.input.Value())
+		if name == "" {
+			m.setMessage("Habit name cannot be empty", "error")
+			return m, nil
+		}
+
+		if err := m.db.AddHabit(name); err != nil {
+			m.setError(err)
+		} else {
+			if err := m.refresh(); err != nil {
+				m.setError(err)
+			} else {
+				m.setMessage(glyph("✓ ", "")+"Habit added!", "success")
+				// Move cursor to the new habit (highest ID), clearing any
+				// tag filter or search that would hide it
+				newest := 0
+				for _, h := range m.habits {
+					newest = max(newest, h.ID)
 				}
+				m.tagFilter = ""
+				m.search = ""
+				m.rebuildRows()
+				m.selectHabit(newest)
 			}
 		}
 
@@ -628,29 +1205,26 @@ func (m *Model) updateAdd(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
 }
 
 func (m *Model) updateDelete(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
-	switch msg.String() {
-	case "y", "Y":
-		if len(m.habits) == 0 {
+	switch {
+	case key.Matches(msg, m.keys.Yes):
+		habit, ok := m.selected()
+		if !ok {
 			m.mode = modeList
 			return m, nil
 		}
 
-		if err := m.db.DeleteHabit(m.habits[m.cursor].ID); err != nil {
+		if err := m.db.DeleteHabit(habit.ID); err != nil {
 			m.setError(err)
 		} else {
 			if err := m.refresh(); err != nil {
 				m.setError(err)
 			} else {
-				// Adjust cursor if needed
-				if m.cursor >= len(m.habits) && m.cursor > 0 {
-					m.cursor--
-				}
-				m.setMessage("✓ Habit deleted", "success")
+				m.setMessage(glyph("✓ ", "")+"Habit deleted", "success")
 			}
 		}
 		m.mode = modeList
 
-	case "n", "N", "esc":
+	case key.Matches(msg, m.keys.No):
 		m.mode = modeList
 	}
 
@@ -658,17 +1232,39 @@ func (m *Model) updateDelete(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
 }
 
 func (m *Model) updateHeatmap(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
-	switch msg.String() {
-	case "esc", "q", "h":
+	switch {
+	case key.Matches(msg, m.keys.Back, m.keys.Heatmap):
 		m.mode = modeList
 
-	case "left":
-		if m.weeks > minWeeks {
-			m.weeks -= weeksStep
+	case key.Matches(msg, m.keys.Help):
+		m.showHelp()
+
+	case key.Matches(msg, m.keys.AllHabits):
+		m.inspected = ""
+		if !m.heatmapAll {
+			m.openCombinedHeatmap()
+			break
+		}
+		habit, ok := m.selected()
+		if !ok {
+			m.setMessage("No habit selected to view", "info")
+			break
+		}
+		if err := m.loadHeatmap(habit.ID); err != nil {
+			m.setError(err)
+			break
+		}
+		m.heatmapAll = false
+
+	case key.Matches(msg, m.keys.WeeksLess):
+		// Step down from what is shown, which may be less than m.weeks on a
+		// narrow terminal
+		if shown := m.heatmapWeeks(); shown > minWeeks {
+			m.weeks = max(shown-weeksStep, minWeeks)
 		}
 
-	case "right":
-		if m.weeks < maxWeeks {
+	case key.Matches(msg, m.keys.WeeksMore):
+		if m.weeks < maxWeeks && m.heatmapWeeks() == m.weeks {
 			m.weeks += weeksStep
 		}
 	}
@@ -676,79 +1272,102 @@ func (m *Model) updateHeatmap(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
 	return m, nil
 }
 
+// updateEdit handles the category and tag prompts for the selected habit.
+func (m *Model) updateEdit(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	switch {
+	case key.Matches(msg, m.keys.Cancel):
+		m.mode = modeList
+		m.input.Blur()
+		return m, nil
+
+	case key.Matches(msg, m.keys.Submit):
+		habit, ok := m.selected()
+		if !ok {
+			m.mode = modeList
+			m.input.Blur()
+			return m, nil
+		}
+
+		var err error
+		if m.mode == modeCategory {
+			err = m.db.SetHabitCategory(habit.ID, m.input.Value())
+		} else {
+			var tags []string
+			if tags, err = parseTags(m.input.Value()); err == nil {
+				err = m.db.SetHabitTags(habit.ID, tags)
+			}
+		}
+
+		if err != nil {
+			m.setError(err)
+			return m, nil
+		}
+
+		if err := m.refresh(); err != nil {
+			m.setError(err)
+		} else {
+			m.selectHabit(habit.ID)
+			if m.mode == modeCategory {
+				m.setMessage(glyph("✓ ", "")+"Category updated", "success")
+			} else {
+				m.setMessage(glyph("✓ ", "")+"Tags updated", "success")
+			}
+		}
+
+		m.mode = modeList
+		m.input.Blur()
+		return m, nil
+	}
+
+	var cmd tea.Cmd
+	m.input, cmd = m.input.Update(msg)
+	return m, cmd
+}
+
+// refresh reloads habits, categories and today's check-ins and lays out
+// the list again.
 func (m *Model) refresh() error {
 	habits, err := m.db.GetHabits()
 	if err != nil {
 		return err
 	}
-	m.habits = habits
-	return nil
-}
-
-// ============================================================
-// VIEW
-// ============================================================
 
-// Get achievements for a habit
-func (m *Model) getAchievements(habit Habit) []string {
-	var achievements []string
-
-	// Streak achievements
-	if habit.CurrentStreak >= 3 {
-		achievements = append(achievements, "🔥 3 Day Streak!")
-	}
-	if habit.CurrentStreak >= 7 {
-		achievements = append(achievements, "⭐ Week Warrior!")
-	}
-	if habit.CurrentStreak >= 30 {
-		achievements = append(achievements, "🏆 Monthly Master!")
-	}
-	if habit.CurrentStreak >= 100 {
-		achievements = append(achievements, "👑 Century Champion!")
-	}
-	if habit.CurrentStreak >= 365 {
-		achievements = append(achievements, "💎 Year Legend!")
+	categories, err := m.db.GetCategories()
+	if err != nil {
+		return err
 	}
 
-	// Completion achievements
-	if habit.TotalDone >= 10 {
-		achievements = append(achievements, "✨ Getting Started (10)")
-	}
-	if habit.TotalDone >= 50 {
-		achievements = append(achievements, "🎯 Half Century (50)")
-	}
-	if habit.TotalDone >= 100 {
-		achievements = append(achievements, "💪 Century Club (100)")
-	}
-	if habit.TotalDone >= 365 {
-		achievements = append(achievements, "🌟 Year Round (365)")
-	}
-	if habit.TotalDone >= 1000 {
-		achievements = append(achievements, "🚀 Thousand Strong (1000)")
+	doneToday, err := m.db.GetDoneOn(time.Now().Format("2006-01-02"))
+	if err != nil {
+		return err
 	}
 
-	// Level achievements
-	if habit.Level >= 5 {
-		achievements = append(achievements, "🌻 Blooming (Level 5)")
-	}
-	if habit.Level >= 10 {
-		achievements = append(achievements, "🌳 Growing Strong (Level 10)")
+	m.habits = habits
+	m.categories = categories
+	m.doneToday = doneToday
+	if err := m.loadRecentLogs(); err != nil {
+		return err
 	}
-	if habit.Level >= 20 {
-		achievements = append(achievements, "👑 Habit Royalty (Level 20)")
+	if err := m.sortHabits(); err != nil {
+		return err
 	}
-	if habit.Level >= 50 {
-		achievements = append(achievements, "🔥 Legendary (Level 50)")
+	if m.tagFilter != "" && !slices.Contains(m.allTags(), m.tagFilter) {
+		m.tagFilter = ""
 	}
-
-	return achievements
+	m.rebuildRows()
+	return nil
 }
 
+// ============================================================
+// VIEW
+// ============================================================
+
 func (m *Model) View() string {
 	var content string
+	m.hits = m.hits[:0]
 
 	switch m.mode {
-	case modeList:
+	case modeList, modeSearch:
 		content = m.viewList()
 	case modeAdd:
 		content = m.viewAdd()
@@ -756,6 +1375,28 @@ func (m *Model) View() string {
 		content = m.viewDelete()
 	case modeHeatmap:
 		content = m.viewHeatmap()
+	case modeCategory, modeTags:
+		content = m.viewEdit()
+	case modeSummary:
+		return m.viewSummary()
+	case modeHelp:
+		content = m.viewHelp()
+	case modeCalendar:
+		content = m.viewCalendar()
+	case modeNote, modeValue:
+		content = m.viewAnnotate()
+	case modeMatrix:
+		content = m.viewMatrix()
+	case modeChart:
+		content = m.viewChart()
+	case modeTiming:
+		content = m.viewTiming()
+	case modeInsights:
+		content = m.viewInsights()
+	case modeGoals:
+		content = m.viewGoals()
+	case modeGoalAdd:
+		content = m.viewGoalAdd()
 	}
 
 	if m.message != "" {
@@ -771,36 +1412,82 @@ func (m *Model) View() string {
 		content += "\n\n" + msgStyle.Render(m.message)
 	}
 
-	return boxStyle.Render(content)
+	view := boxStyle.Render(content)
+	m.viewHeight = lipgloss.Height(view)
+	return view
 }
 
 func (m *Model) viewList() string {
-	var s strings.Builder
+	var header strings.Builder
+
+	header.WriteString(titleStyle.Render(glyph("⚡️  HABIT TRACKER  ⚡️", "HABIT TRACKER")) + "\n\n")
 
-	s.WriteString(titleStyle.Render("⚡️  HABIT TRACKER  ⚡️") + "\n\n")
+	if m.mode == modeSearch {
+		header.WriteString("/" + m.input.View() + "\n\n")
+	} else if m.search != "" {
+		header.WriteString(warningStyle.Render("Search: "+m.search) + dimStyle.Render("  (/: edit, esc: clear)") + "\n\n")
+	}
+
+	if m.tagFilter != "" || m.sortMode != sortManual {
+		var status []string
+		if m.tagFilter != "" {
+			status = append(status, "Filter: #"+m.tagFilter)
+		}
+		if m.sortMode != sortManual {
+			status = append(status, "Sort: "+m.sortMode.String())
+		}
+		header.WriteString(warningStyle.Render(strings.Join(status, glyph(" · ", " | "))) + "\n\n")
+	}
+
+	// Body lines, remembering which lines belong to the cursor row so the
+	// viewport can keep it in sight
+	var lines []string
+	var lineRows []int // row of each line, -1 for spacing
+	cursorStart, cursorEnd := 0, 0
 
 	if len(m.habits) == 0 {
-		s.WriteString(dimStyle.Render("No habits yet. Press 'a' to add your first habit!\n"))
+		lines = append(lines, dimStyle.Render("No habits yet. Press '"+m.keys.Add.Help().Key+"' to add your first habit!"))
+		lineRows = append(lineRows, -1)
 	} else {
-		for i, habit := range m.habits {
+		grouped := m.grouped()
+		for i, row := range m.rows {
+			if i == m.cursor {
+				cursorStart = len(lines)
+			}
+
+			if row.header {
+				if i > 0 {
+					lines = append(lines, "")
+					lineRows = append(lineRows, -1)
+					if i == m.cursor {
+						cursorStart++
+					}
+				}
+				lines = append(lines, m.renderCategoryHeader(row.category, i == m.cursor))
+				lineRows = append(lineRows, i)
+				if i == m.cursor {
+					cursorEnd = len(lines)
+				}
+				continue
+			}
+
+			habit := m.habits[row.habit]
 			cursor := "  "
 			style := normalStyle
 
 			if i == m.cursor {
-				cursor = "› "
+				cursor = glyph("› ", "> ")
 				style = selectedStyle
 			}
 
-			// Check if done today
-			today := time.Now().Format("2006-01-02")
-			isDone := false
-			if logs, err := m.db.GetLogs(habit.ID, 1); err == nil {
-				isDone = logs[today]
+			indent := ""
+			if grouped {
+				indent = "  "
 			}
 
-			status := "○"
-			if isDone {
-				status = "✓"
+			status := glyph("○", "[ ]")
+			if m.doneToday[habit.ID] {
+				status = glyph("✓", "[x]")
 			}
 
 			// Level badge
@@ -810,50 +1497,93 @@ func (m *Model) viewList() string {
 			xpInLevel := habit.XP % 100
 			xpBar := m.getProgressBar(xpInLevel, 100, 10)
 
-			line := fmt.Sprintf("%s%s %s %s", cursor, status, habit.Name, levelBadge)
-			streakInfo := fmt.Sprintf("  [🔥 %d | 💎 %d coins]", habit.CurrentStreak, habit.Coins)
+			// Render the name separately so search matches can be picked out
+			positions, _ := m.searchMatch(habit)
+			base := style.UnsetPadding()
+			pad := strings.Repeat(" ", style.GetPaddingLeft())
+			line := base.Render(fmt.Sprintf("%s%s%s%s ", pad, indent, cursor, status)) +
+				highlight(habit.Name, positions, base) +
+				base.Render(" "+levelBadge+strings.Repeat(" ", style.GetPaddingRight()))
+			streakInfo := fmt.Sprintf(glyph("  [🔥 %d | 💎 %d coins | 💪 %.0f%%", "  [streak %d | %d coins | strength %.0f%%"), habit.CurrentStreak, habit.Coins, habit.Strength)
+			trend := " " + lipgloss.NewStyle().Foreground(theme.Success).Render(m.habitSparkline(habit.ID))
 
-			s.WriteString(style.Render(line))
 			if i == m.cursor {
-				s.WriteString(streakStyle.Render(streakInfo))
-				s.WriteString("\n")
-				s.WriteString(dimStyle.Render(fmt.Sprintf("     %s %d/%d XP", xpBar, xpInLevel, 100)))
+				lines = append(lines, line+streakStyle.Render(streakInfo)+" "+strengthArrow(habit.StrengthTrend)+streakStyle.Render("]")+trend+renderTags(habit.Tags))
+				lines = append(lines, dimStyle.Render(fmt.Sprintf("%s     %s %d/%d XP", indent, xpBar, xpInLevel, 100)))
+				lineRows = append(lineRows, i, i)
+				cursorEnd = len(lines)
+			} else {
+				lines = append(lines, line+dimStyle.Render(streakInfo)+" "+strengthArrow(habit.StrengthTrend)+dimStyle.Render("]")+trend+renderTags(habit.Tags))
+				lineRows = append(lineRows, i)
+			}
+		}
+
+		if len(m.rows) == 0 {
+			if m.search != "" {
+				lines = append(lines, dimStyle.Render("No habits match \""+m.search+"\""))
 			} else {
-				s.WriteString(dimStyle.Render(streakInfo))
+				lines = append(lines, dimStyle.Render("No habits tagged #"+m.tagFilter))
 			}
-			s.WriteString("\n")
+			lineRows = append(lineRows, -1)
 		}
 	}
 
-	s.WriteString("\n")
-	s.WriteString(dimStyle.Render("↑/↓: navigate | enter: toggle | a: add | d: delete | h: heatmap | q: quit"))
+	var footer strings.Builder
+	footer.WriteString("\n")
+	footer.WriteString(m.renderHelp())
 
-	return s.String()
+	// Whatever height is left after the header, footer, box and message
+	// goes to the list
+	avail := 0
+	if m.height > 0 {
+		avail = m.height - strings.Count(header.String(), "\n") - lipgloss.Height(footer.String()) - boxStyle.GetVerticalFrameSize()
+		if m.message != "" {
+			avail -= 2
+		}
+	}
+
+	body, shown := m.scrollList(lines, cursorStart, cursorEnd, avail)
+
+	// Clicking anywhere on a row's lines selects it
+	top := strings.Count(header.String(), "\n")
+	for y, i := range shown {
+		if i >= 0 && lineRows[i] >= 0 {
+			m.addHit(hitZone{y: top + y, x0: 0, x1: math.MaxInt, row: lineRows[i]})
+		}
+	}
+
+	return header.String() + body + "\n" + footer.String()
+}
+
+// levelBadges are in ascending level order; a habit shows the last one it
+// has reached.
+var levelBadges = []struct {
+	Level int
+	Icon  string
+	Name  string
+}{
+	{1, "🌱", "Seedling"},
+	{2, "🌿", "Herb"},
+	{3, "🍀", "Clover"},
+	{5, "🌻", "Sunflower"},
+	{10, "🌳", "Tree"},
+	{15, "🏆", "Trophy"},
+	{20, "👑", "Crown"},
+	{25, "⭐", "Star"},
+	{30, "💫", "Comet"},
+	{50, "🔥", "Fire"},
 }
 
 func (m *Model) getLevelBadge(level int) string {
-	badges := map[int]string{
-		1:  "🌱", // Seedling
-		2:  "🌿", // Herb
-		3:  "🍀", // Clover
-		5:  "🌻", // Sunflower
-		10: "🌳", // Tree
-		15: "🏆", // Trophy
-		20: "👑", // Crown
-		25: "⭐", // Star
-		30: "💫", // Dizzy
-		50: "🔥", // Fire
-	}
-
-	badge := "🌱"
-	for lvl, b := range badges {
-		if level >= lvl {
-			badge = b
+	badge := glyph(levelBadges[0].Icon, levelBadges[0].Name)
+	for _, b := range levelBadges {
+		if level >= b.Level {
+			badge = glyph(b.Icon, b.Name)
 		}
 	}
 
 	return lipgloss.NewStyle().
-		Foreground(lipgloss.Color("#FFA500")).
+		Foreground(theme.Warning).
 		Render(fmt.Sprintf("[Lv.%d %s]", level, badge))
 }
 
@@ -864,14 +1594,14 @@ func (m *Model) getProgressBar(current, max, width int) string {
 	bar := ""
 	for i := 0; i < width; i++ {
 		if i < filled {
-			bar += "█"
+			bar += glyph("█", "#")
 		} else {
-			bar += "░"
+			bar += glyph("░", "-")
 		}
 	}
 
 	return lipgloss.NewStyle().
-		Foreground(lipgloss.Color("#7D56F4")).
+		Foreground(theme.Accent).
 		Render(bar)
 }
 
@@ -880,80 +1610,215 @@ func (m *Model) viewAdd() string {
 
 	s.WriteString(titleStyle.Render("Add New Habit") + "\n\n")
 	s.WriteString(m.input.View() + "\n\n")
-	s.WriteString(dimStyle.Render("enter: save | esc: cancel"))
+	s.WriteString(m.renderHelp())
+
+	return s.String()
+}
+
+func (m *Model) viewEdit() string {
+	habit, _ := m.selected()
+
+	var s strings.Builder
+
+	title := "Set Category"
+	if m.mode == modeTags {
+		title = "Set Tags"
+	}
+	s.WriteString(titleStyle.Render(title) + "\n\n")
+	s.WriteString(normalStyle.Render(habit.Name) + "\n\n")
+	s.WriteString(m.input.View() + "\n\n")
+	if m.mode == modeCategory {
+		var names []string
+		for _, c := range m.categories {
+			names = append(names, glyph(c.Icon+" ", "")+c.Name)
+		}
+		if len(names) > 0 {
+			s.WriteString(dimStyle.Render("Existing: "+strings.Join(names, ", ")) + "\n")
+		}
+	}
+	s.WriteString(m.renderHelp())
 
 	return s.String()
 }
 
 func (m *Model) viewDelete() string {
-	if len(m.habits) == 0 {
+	habit, ok := m.selected()
+	if !ok {
 		return ""
 	}
 
 	var s strings.Builder
 
-	s.WriteString(errorStyle.Render("⚠  Delete Habit?") + "\n\n")
-	s.WriteString(fmt.Sprintf("Are you sure you want to delete '%s'?\n", m.habits[m.cursor].Name))
+	s.WriteString(errorStyle.Render(glyph("⚠  ", "")+"Delete Habit?") + "\n\n")
+	s.WriteString(fmt.Sprintf("Are you sure you want to delete '%s'?\n", habit.Name))
 	s.WriteString(dimStyle.Render("This will remove all history for this habit.\n\n"))
-	s.WriteString(dimStyle.Render("y: yes | n: no"))
+	s.WriteString(m.renderHelp())
 
 	return s.String()
 }
 
 func (m *Model) viewHeatmap() string {
-	if len(m.habits) == 0 {
+	if m.heatmapAll {
+		return m.viewCombinedHeatmap()
+	}
+
+	habit, ok := m.selected()
+	if !ok {
 		return ""
 	}
 
 	var s strings.Builder
-	habit := m.habits[m.cursor]
 
 	// Header with habit name and streak
 	headerBox := lipgloss.NewStyle().
 		Bold(true).
-		Foreground(lipgloss.Color("#7D56F4")).
-		Border(lipgloss.RoundedBorder()).
-		BorderForeground(lipgloss.Color("#7D56F4")).
+		Foreground(theme.Accent).
+		Border(roundedBorder()).
+		BorderForeground(theme.Accent).
 		Padding(0, 2).
 		MarginBottom(1)
 
-	headerContent := fmt.Sprintf("📊 %s  %s",
+	headerContent := fmt.Sprintf("%s%s  %s",
+		glyph("📊 ", ""),
 		habit.Name,
-		streakStyle.Render(fmt.Sprintf("🔥 %d day streak", habit.CurrentStreak)))
+		streakStyle.Render(fmt.Sprintf(glyph("🔥 ", "")+"%d day streak", habit.CurrentStreak)))
 
 	s.WriteString(headerBox.Render(headerContent) + "\n\n")
 
 	// Generate heatmap with proper date alignment
 	endDate := time.Now()
-	startDate := endDate.AddDate(0, 0, -(m.weeks*7)+1)
+	weeks := m.heatmapWeeks()
+	startDate, totalDays, numWeeks := heatmapWindow(weeks, endDate)
 
-	// Adjust start to Sunday
-	for startDate.Weekday() != time.Sunday {
-		startDate = startDate.AddDate(0, 0, -1)
+	grid := m.renderHeatmapGrid(strings.Count(s.String(), "\n"), startDate, endDate, numWeeks, func(date string) (string, lipgloss.Color) {
+		if m.logs[date] {
+			return heatmapSymbol(true), colorLevel4
+		}
+		return heatmapSymbol(false), colorNone
+	})
+	s.WriteString(grid + "\n\n")
+
+	// Stats section in a nice grid
+	statsBox := m.panelStyle()
+
+	// Calculate completion rate for visible period
+	daysShown, completionRate := completionStats(m.logs, startDate, endDate, totalDays)
+
+	var stats strings.Builder
+	stats.WriteString(subtitleStyle.Render(glyph("📈 ", "")+"Statistics") + "\n\n")
+
+	// Create stat rows
+	statRow := func(label, value string, color lipgloss.Color) string {
+		labelStyle := lipgloss.NewStyle().
+			Foreground(theme.Label).
+			Width(20).
+			Render(label)
+
+		valueStyle := lipgloss.NewStyle().
+			Foreground(color).
+			Bold(true).
+			Render(value)
+
+		return labelStyle + valueStyle
 	}
 
-	// Calculate total days to display
-	totalDays := int(endDate.Sub(startDate).Hours()/24) + 1
-	numWeeks := (totalDays + 6) / 7
+	// Calculate XP in current level
+	xpInLevel := habit.XP % 100
+	xpToNext := 100 - xpInLevel
+
+	stats.WriteString(statRow("Level:", fmt.Sprintf("%d %s", habit.Level, m.getLevelBadge(habit.Level)), theme.Warning) + "\n")
+	stats.WriteString(statRow("Experience:", fmt.Sprintf("%d XP (%d to next)", habit.XP, xpToNext), theme.Accent) + "\n")
+	stats.WriteString(statRow("Coins:", fmt.Sprintf("%d"+glyph(" 💎", ""), habit.Coins), theme.Highlight) + "\n\n")
+	stats.WriteString(statRow("Current Streak:", fmt.Sprintf("%d days", habit.CurrentStreak), theme.Warning) + "\n")
+	stats.WriteString(statRow("Strength:", fmt.Sprintf("%.0f%% (%+.0f this week)", habit.Strength, habit.StrengthTrend), theme.Success) + "\n")
+	stats.WriteString(statRow("Total Completions:", fmt.Sprintf("%d times", habit.TotalDone), theme.Success) + "\n")
+	stats.WriteString(statRow("Completion Rate:", fmt.Sprintf("%.1f%%", completionRate), theme.Accent) + "\n")
+	stats.WriteString(statRow("Period Shown:", fmt.Sprintf("%d days", daysShown), theme.Dim) + "\n")
+
+	// Best streak calculation
+	bestStreak := calculateBestStreak(m.logs)
+	stats.WriteString(statRow("Best Streak:", fmt.Sprintf("%d days", bestStreak), theme.Error) + "\n\n")
+
+	// Achievements
+	stats.WriteString(subtitleStyle.Render(glyph("🏆 ", "")+"Achievements") + "\n")
+	achievements := achievementLabels(m.achievements)
+	if len(achievements) > 0 {
+		for _, ach := range achievements {
+			stats.WriteString("  " + successStyle.Render(ach) + "\n")
+		}
+	} else {
+		stats.WriteString(dimStyle.Render("  Keep going to unlock achievements!\n"))
+	}
+
+	// Recent check-ins in a cleaner format
+	recentBox := m.panelStyle()
+
+	var recent strings.Builder
+	recent.WriteString(subtitleStyle.Render(glyph("⏱️  ", "")+"Recent Check-ins") + "\n\n")
+
+	checkIns := recentCheckIns(m.logsWithTime, endDate)
+	for _, c := range checkIns {
+		recent.WriteString(fmt.Sprintf("%s  %s  %s\n",
+			successStyle.Render(glyph("✓", "x")),
+			lipgloss.NewStyle().Foreground(theme.Label).Width(15).Render(c.Date),
+			dimStyle.Render(c.Time+glyph(" • ", " - ")+c.Ago)))
+	}
+
+	if len(checkIns) == 0 {
+		recent.WriteString(dimStyle.Render("  No recent check-ins in the last 7 days\n"))
+	}
+
+	// Side by side when there is room for both
+	s.WriteString(m.joinPanels(statsBox.Render(stats.String()), recentBox.Render(recent.String())) + "\n\n")
+
+	// Legend
+	legendBox := lipgloss.NewStyle().
+		Foreground(theme.Dim).
+		Padding(0, 1)
+
+	legend := fmt.Sprintf("Legend:  %s No activity   %s Completed   [%s] Today",
+		lipgloss.NewStyle().Foreground(colorNone).Render(heatmapSymbol(false)),
+		lipgloss.NewStyle().Foreground(colorLevel4).Render(heatmapSymbol(true)),
+		heatmapSymbol(true))
 
-	// Build heatmap section
-	heatmapBox := lipgloss.NewStyle().
-		Border(lipgloss.NormalBorder()).
-		BorderForeground(lipgloss.Color("#3C3C3C")).
-		Padding(1, 2)
+	showing := fmt.Sprintf("Showing %d weeks", weeks)
+	if weeks < m.weeks {
+		showing = fmt.Sprintf("Showing %d of %d weeks (window too narrow)", weeks, m.weeks)
+	}
+	if width := m.contentWidth(); width > 0 && lipgloss.Width(legend)+5+len(showing) > width {
+		legend += "\n" + showing
+	} else {
+		legend += "     " + showing
+	}
 
+	s.WriteString(legendBox.Render(legend) + "\n\n")
+
+	// Controls
+	s.WriteString(m.renderHelp())
+
+	return s.String()
+}
+
+// renderHeatmapGrid draws the boxed week-by-day grid from startDate to
+// endDate, taking each day's symbol and color from cell. top is the content
+// line the box starts on, so the cells can be clicked.
+func (m *Model) renderHeatmapGrid(top int, startDate, endDate time.Time, numWeeks int, cell func(date string) (string, lipgloss.Color)) string {
 	var heatmap strings.Builder
 
+	// Where the grid starts inside the content, for mouse clicks
+	gridTop := top + heatmapBoxStyle.GetBorderTopSize() + heatmapBoxStyle.GetPaddingTop()
+	gridLeft := heatmapBoxStyle.GetBorderLeftSize() + heatmapBoxStyle.GetPaddingLeft() + 8
+
 	// Day labels and heatmap grid
 	days := []string{"Sun", "Mon", "Tue", "Wed", "Thu", "Fri", "Sat"}
 	dayColors := []lipgloss.Color{
-		lipgloss.Color("#888888"), // Sun
-		lipgloss.Color("#AAAAAA"), // Mon
-		lipgloss.Color("#AAAAAA"), // Tue
-		lipgloss.Color("#AAAAAA"), // Wed
-		lipgloss.Color("#AAAAAA"), // Thu
-		lipgloss.Color("#AAAAAA"), // Fri
-		lipgloss.Color("#888888"), // Sat
+		theme.Weekend, // Sun
+		theme.Label,   // Mon
+		theme.Label,   // Tue
+		theme.Label,   // Wed
+		theme.Label,   // Thu
+		theme.Label,   // Fri
+		theme.Weekend, // Sat
 	}
 
 	for day := 0; day < 7; day++ {
@@ -976,43 +1841,80 @@ func (m *Model) viewHeatmap() string {
 			}
 
 			dateStr := date.Format("2006-01-02")
-			color := colorNone
-			symbol := "  "
+			symbol, color := cell(dateStr)
 
-			if m.logs[dateStr] {
-				color = colorLevel4
-				symbol = "██"
-			} else {
-				symbol = "░░"
+			x := gridLeft + week*4
+			m.addHit(hitZone{y: gridTop + day, x0: x, x1: x + 4, row: -1, date: dateStr})
+
+			cellStyle := lipgloss.NewStyle().Foreground(color)
+			if dateStr == m.inspected {
+				cellStyle = cellStyle.Background(theme.Selection)
 			}
 
 			// Add border for today
 			if dateStr == time.Now().Format("2006-01-02") {
-				cell := lipgloss.NewStyle().
-					Foreground(color).
-					Bold(true).
-					Render("[" + symbol + "]")
-				heatmap.WriteString(cell)
+				heatmap.WriteString(cellStyle.Bold(true).Render("[" + symbol + "]"))
 			} else {
-				cell := lipgloss.NewStyle().
-					Foreground(color).
-					Render(" " + symbol + " ")
-				heatmap.WriteString(cell)
+				heatmap.WriteString(cellStyle.Render(" " + symbol + " "))
 			}
 		}
 		heatmap.WriteString("\n")
 	}
 
-	s.WriteString(heatmapBox.Render(heatmap.String()) + "\n\n")
+	return heatmapBoxStyle.Render(heatmap.String())
+}
 
-	// Stats section in a nice grid
-	statsBox := lipgloss.NewStyle().
-		Border(lipgloss.RoundedBorder()).
-		BorderForeground(lipgloss.Color("#7D56F4")).
-		Padding(1, 2).
-		Width(50)
+// heatmapWindow returns the Sunday-aligned start date of a heatmap showing the
+// given number of weeks up to endDate, with the total days and weeks covered.
+func heatmapWindow(weeks int, endDate time.Time) (time.Time, int, int) {
+	startDate := endDate.AddDate(0, 0, -(weeks*7)+1)
 
-	// Calculate completion rate for visible period
+	// Adjust start to Sunday
+	for startDate.Weekday() != time.Sunday {
+		startDate = startDate.AddDate(0, 0, -1)
+	}
+
+	// Calculate total days to display
+	totalDays := int(endDate.Sub(startDate).Hours()/24) + 1
+	numWeeks := (totalDays + 6) / 7
+
+	return startDate, totalDays, numWeeks
+}
+
+type heatmapCell struct {
+	Date    string `json:"date"`
+	Done    bool   `json:"done"`
+	Today   bool   `json:"today"`
+	InRange bool   `json:"inRange"`
+}
+
+// heatmapCells lays logs out like viewHeatmap: 7 rows (Sun..Sat) with one
+// cell per week.
+func heatmapCells(logs map[string]bool, weeks int, endDate time.Time) [][]heatmapCell {
+	startDate, _, numWeeks := heatmapWindow(weeks, endDate)
+	today := endDate.Format("2006-01-02")
+
+	days := make([][]heatmapCell, 7)
+	for day := 0; day < 7; day++ {
+		days[day] = make([]heatmapCell, numWeeks)
+		for week := 0; week < numWeeks; week++ {
+			date := startDate.AddDate(0, 0, week*7+day)
+			dateStr := date.Format("2006-01-02")
+			days[day][week] = heatmapCell{
+				Date:    dateStr,
+				Done:    logs[dateStr],
+				Today:   dateStr == today,
+				InRange: !date.After(endDate),
+			}
+		}
+	}
+
+	return days
+}
+
+// completionStats returns the number of days shown in a heatmap window and the
+// percentage of them that were completed.
+func completionStats(logs map[string]bool, startDate, endDate time.Time, totalDays int) (int, float64) {
 	daysShown := 0
 	daysCompleted := 0
 	for i := 0; i < totalDays; i++ {
@@ -1020,7 +1922,7 @@ func (m *Model) viewHeatmap() string {
 		if !checkDate.After(endDate) {
 			daysShown++
 			dateStr := checkDate.Format("2006-01-02")
-			if m.logs[dateStr] {
+			if logs[dateStr] {
 				daysCompleted++
 			}
 		}
@@ -1031,122 +1933,52 @@ func (m *Model) viewHeatmap() string {
 		completionRate = float64(daysCompleted) / float64(daysShown) * 100
 	}
 
-	var stats strings.Builder
-	stats.WriteString(subtitleStyle.Render("📈 Statistics") + "\n\n")
-
-	// Create stat rows
-	statRow := func(label, value, color string) string {
-		labelStyle := lipgloss.NewStyle().
-			Foreground(lipgloss.Color("#AAAAAA")).
-			Width(20).
-			Render(label)
-
-		valueStyle := lipgloss.NewStyle().
-			Foreground(lipgloss.Color(color)).
-			Bold(true).
-			Render(value)
-
-		return labelStyle + valueStyle
-	}
-
-	// Calculate XP in current level
-	xpInLevel := habit.XP % 100
-	xpToNext := 100 - xpInLevel
-
-	stats.WriteString(statRow("Level:", fmt.Sprintf("%d %s", habit.Level, m.getLevelBadge(habit.Level)), "#FFA500") + "\n")
-	stats.WriteString(statRow("Experience:", fmt.Sprintf("%d XP (%d to next)", habit.XP, xpToNext), "#7D56F4") + "\n")
-	stats.WriteString(statRow("Coins:", fmt.Sprintf("%d 💎", habit.Coins), "#FFD700") + "\n\n")
-	stats.WriteString(statRow("Current Streak:", fmt.Sprintf("%d days", habit.CurrentStreak), "#FFA500") + "\n")
-	stats.WriteString(statRow("Total Completions:", fmt.Sprintf("%d times", habit.TotalDone), "#39D353") + "\n")
-	stats.WriteString(statRow("Completion Rate:", fmt.Sprintf("%.1f%%", completionRate), "#7D56F4") + "\n")
-	stats.WriteString(statRow("Period Shown:", fmt.Sprintf("%d days", daysShown), "#626262") + "\n")
+	return daysShown, completionRate
+}
 
-	// Best streak calculation
-	bestStreak := m.calculateBestStreak(m.logs)
-	stats.WriteString(statRow("Best Streak:", fmt.Sprintf("%d days", bestStreak), "#FF6B6B") + "\n\n")
+// recentCheckIn is a check-in as the heatmap view lists it.
+type recentCheckIn struct {
+	Date string `json:"date"` // e.g. Mon, Jan 2
+	Time string `json:"time"` // e.g. 3:04 PM
+	Ago  string `json:"ago"`  // Today, Yesterday or N days ago
+}
 
-	// Achievements
-	stats.WriteString(subtitleStyle.Render("🏆 Achievements") + "\n")
-	achievements := m.getAchievements(habit)
-	if len(achievements) > 0 {
-		for _, ach := range achievements {
-			stats.WriteString("  " + successStyle.Render(ach) + "\n")
+// recentCheckIns lists the latest check-ins in the recentLogDays up to
+// end, newest first, shared by the heatmap view and the dashboard.
+func recentCheckIns(logs map[string]LogEntry, end time.Time) []recentCheckIn {
+	var checkIns []recentCheckIn
+	for i := 0; i < recentLogDays && len(checkIns) < maxRecentShow; i++ {
+		checkDate := end.AddDate(0, 0, -i)
+		entry, exists := logs[checkDate.Format("2006-01-02")]
+		if !exists {
+			continue
 		}
-	} else {
-		stats.WriteString(dimStyle.Render("  Keep going to unlock achievements!\n"))
-	}
-
-	s.WriteString(statsBox.Render(stats.String()) + "\n\n")
-
-	// Recent check-ins in a cleaner format
-	recentBox := lipgloss.NewStyle().
-		Border(lipgloss.RoundedBorder()).
-		BorderForeground(lipgloss.Color("#7D56F4")).
-		Padding(1, 2).
-		Width(50)
 
-	var recent strings.Builder
-	recent.WriteString(subtitleStyle.Render("⏱️  Recent Check-ins") + "\n\n")
-
-	count := 0
-	for i := 0; i < recentLogDays && count < maxRecentShow; i++ {
-		checkDate := endDate.AddDate(0, 0, -i)
-		dateStr := checkDate.Format("2006-01-02")
-
-		if entry, exists := m.logsWithTime[dateStr]; exists {
-			timestamp, err := time.Parse("2006-01-02 15:04:05", entry.Timestamp)
-			if err != nil {
-				continue
-			}
-
-			timeStr := timestamp.Format("3:04 PM")
-			dateDisplay := checkDate.Format("Mon, Jan 2")
-
-			// Calculate days ago
-			daysAgo := int(time.Now().Sub(checkDate).Hours() / 24)
-			daysAgoStr := ""
-			if daysAgo == 0 {
-				daysAgoStr = "Today"
-			} else if daysAgo == 1 {
-				daysAgoStr = "Yesterday"
-			} else {
-				daysAgoStr = fmt.Sprintf("%d days ago", daysAgo)
-			}
+		timestamp, err := time.Parse("2006-01-02 15:04:05", entry.Timestamp)
+		if err != nil {
+			continue
+		}
 
-			recent.WriteString(fmt.Sprintf("%s  %s  %s\n",
-				successStyle.Render("✓"),
-				lipgloss.NewStyle().Foreground(lipgloss.Color("#AAAAAA")).Width(15).Render(dateDisplay),
-				lipgloss.NewStyle().Foreground(lipgloss.Color("#626262")).Render(timeStr+" • "+daysAgoStr)))
-			count++
+		// Calculate days ago
+		daysAgo := int(time.Now().Sub(checkDate).Hours() / 24)
+		ago := fmt.Sprintf("%d days ago", daysAgo)
+		if daysAgo == 0 {
+			ago = "Today"
+		} else if daysAgo == 1 {
+			ago = "Yesterday"
 		}
-	}
 
-	if count == 0 {
-		recent.WriteString(dimStyle.Render("  No recent check-ins in the last 7 days\n"))
+		checkIns = append(checkIns, recentCheckIn{
+			Date: checkDate.Format("Mon, Jan 2"),
+			Time: timestamp.Format("3:04 PM"),
+			Ago:  ago,
+		})
 	}
-
-	s.WriteString(recentBox.Render(recent.String()) + "\n\n")
-
-	// Legend
-	legendBox := lipgloss.NewStyle().
-		Foreground(lipgloss.Color("#626262")).
-		Padding(0, 1)
-
-	legend := fmt.Sprintf("Legend:  %s No activity   %s Completed   [██] Today     Showing %d weeks",
-		lipgloss.NewStyle().Foreground(colorNone).Render("░░"),
-		lipgloss.NewStyle().Foreground(colorLevel4).Render("██"),
-		m.weeks)
-
-	s.WriteString(legendBox.Render(legend) + "\n\n")
-
-	// Controls
-	s.WriteString(dimStyle.Render("←/→: adjust weeks (±4) | esc/h/q: back to list"))
-
-	return s.String()
+	return checkIns
 }
 
 // Helper function to calculate best streak
-func (m *Model) calculateBestStreak(logs map[string]bool) int {
+func calculateBestStreak(logs map[string]bool) int {
 	if len(logs) == 0 {
 		return 0
 	}
@@ -1197,16 +2029,98 @@ func (m *Model) calculateBestStreak(logs map[string]bool) int {
 // ============================================================
 
 func main() {
-	m, err := NewModel()
+	if len(os.Args) > 1 && !strings.HasPrefix(os.Args[1], "-") {
+		if err := runCommand(os.Args[1], os.Args[2:]); err != nil {
+			var code exitCode
+			if errors.As(err, &code) {
+				os.Exit(int(code))
+			}
+			fmt.Printf("Error: %v\n", err)
+			os.Exit(1)
+		}
+		return
+	}
+
+	fset := flag.NewFlagSet("habit", flag.ExitOnError)
+	themeName := fset.String("theme", "", "color theme (see `habit themes`)")
+	ascii := fset.Bool("ascii", false, "plain ASCII output without emoji or box drawing")
+	noMouse := fset.Bool("no-mouse", false, "disable mouse support, leaving text selection to the terminal")
+	fset.Parse(os.Args[1:])
+
+	cfg, err := loadConfig()
+	if err != nil {
+		fmt.Printf("Error loading config: %v\n", err)
+		os.Exit(1)
+	}
+
+	// The flag wins over the config file
+	if *themeName == "" {
+		*themeName = cfg.Theme
+	}
+	if *themeName != "" {
+		t, err := loadTheme(*themeName)
+		if err != nil {
+			fmt.Printf("Error loading theme: %v\n", err)
+			os.Exit(1)
+		}
+		applyTheme(t)
+	}
+	if *ascii || cfg.ASCII {
+		setASCII(true)
+	}
+
+	keys, err := loadKeyMap(cfg.Keys)
+	if err != nil {
+		fmt.Printf("Error loading key bindings: %v\n", err)
+		os.Exit(1)
+	}
+
+	m, err := NewModel(keys)
 	if err != nil {
 		fmt.Printf("Error initializing: %v\n", err)
 		os.Exit(1)
 	}
 	defer m.db.Close()
 
-	p := tea.NewProgram(m, tea.WithAltScreen())
+	closeIntegrations := attachIntegrations(m.db, cfg)
+	defer closeIntegrations()
+
+	opts := []tea.ProgramOption{tea.WithAltScreen()}
+	if !*noMouse && !cfg.NoMouse {
+		opts = append(opts, tea.WithMouseCellMotion())
+	}
+
+	p := tea.NewProgram(m, opts...)
 	if _, err := p.Run(); err != nil {
 		fmt.Printf("Error running program: %v\n", err)
 		os.Exit(1)
 	}
 }
+
+// runCommand dispatches the non-interactive subcommands.
+func runCommand(name string, args []string) error {
+	switch name {
+	case "serve":
+		return runServe(args)
+	case "webhooks":
+		return runWebhooks(args)
+	case "remind":
+		return runRemind(args)
+	case "status":
+		return runStatus(args)
+	case "export":
+		return runExport(args)
+	case "heatmap":
+		return runHeatmap(args)
+	case "report":
+		return runReport(args)
+	case "category":
+		return runCategory(args)
+	case "themes":
+		return runThemes(args)
+	case "summary":
+		return runSummary(args)
+	default:
+		return fmt.Errorf("unknown command %q", name)
+	}
+}
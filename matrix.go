@@ -0,0 +1,320 @@
+package main
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/charmbracelet/bubbles/key"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+	"github.com/charmbracelet/x/ansi"
+)
+
+// ============================================================
+// OVERVIEW GRID
+// ============================================================
+
+// matrixRanges are the day counts the overview cycles through.
+var matrixRanges = []int{7, 14, 30}
+
+// Widths of the overview's name, day and streak columns.
+const (
+	matrixNameWidth   = 18
+	matrixCellWidth   = 3
+	matrixStreakWidth = 8
+)
+
+// GetLogsSince returns every check-in on or after start, by habit ID and
+// date, in one query.
+func (d *Database) GetLogsSince(start string) (map[int]map[string]bool, error) {
+	rows, err := d.db.Query("SELECT habit_id, date FROM logs WHERE date >= ?", start)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get logs since %s: %w", start, err)
+	}
+	defer rows.Close()
+
+	logs := make(map[int]map[string]bool)
+	for rows.Next() {
+		var id int
+		var date string
+		if err := rows.Scan(&id, &date); err != nil {
+			return nil, fmt.Errorf("failed to scan log: %w", err)
+		}
+		if logs[id] == nil {
+			logs[id] = make(map[string]bool)
+		}
+		logs[id][date] = true
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating logs: %w", err)
+	}
+
+	return logs, nil
+}
+
+// openMatrix shows the overview with today focused, keeping the list's
+// selected habit.
+func (m *Model) openMatrix() {
+	if len(m.rows) == 0 {
+		m.setMessage("No habits to show", "info")
+		return
+	}
+	if err := m.loadMatrix(); err != nil {
+		m.setError(err)
+		return
+	}
+	if m.rows[m.cursor].header {
+		m.moveHabitCursor(1)
+	}
+	m.matrixCol = 0
+	m.mode = modeMatrix
+}
+
+// loadMatrix loads check-ins for the longest range so switching ranges
+// needs no reload.
+func (m *Model) loadMatrix() error {
+	longest := matrixRanges[len(matrixRanges)-1]
+	start := time.Now().AddDate(0, 0, -(longest - 1)).Format("2006-01-02")
+	logs, err := m.db.GetLogsSince(start)
+	if err != nil {
+		return err
+	}
+	m.matrixLogs = logs
+	return nil
+}
+
+// moveHabitCursor moves the cursor to the next habit row in direction dir,
+// skipping category headers, and stays put if there is none.
+func (m *Model) moveHabitCursor(dir int) {
+	for i := m.cursor + dir; i >= 0 && i < len(m.rows); i += dir {
+		if !m.rows[i].header {
+			m.cursor = i
+			return
+		}
+	}
+}
+
+// matrixDays is the number of day columns shown: the chosen range, cut
+// down to what fits the terminal width.
+func (m *Model) matrixDays() int {
+	days := matrixRanges[m.matrixRange]
+	width := m.contentWidth()
+	if width == 0 {
+		return days
+	}
+	fit := (width - matrixNameWidth - matrixStreakWidth) / matrixCellWidth
+	return max(min(days, fit), 1)
+}
+
+func (m *Model) updateMatrix(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	m.message = ""
+	m.err = nil
+
+	switch {
+	case key.Matches(msg, m.keys.Back, m.keys.Overview):
+		m.mode = modeList
+
+	case key.Matches(msg, m.keys.Help):
+		m.showHelp()
+
+	case key.Matches(msg, m.keys.Up):
+		m.moveHabitCursor(-1)
+
+	case key.Matches(msg, m.keys.Down):
+		m.moveHabitCursor(1)
+
+	case key.Matches(msg, m.keys.PrevDay):
+		m.matrixCol = min(m.matrixCol+1, m.matrixDays()-1)
+
+	case key.Matches(msg, m.keys.NextDay):
+		m.matrixCol = max(m.matrixCol-1, 0)
+
+	case key.Matches(msg, m.keys.Today):
+		m.matrixCol = 0
+
+	case key.Matches(msg, m.keys.Range):
+		m.matrixRange = (m.matrixRange + 1) % len(matrixRanges)
+		m.matrixCol = min(m.matrixCol, m.matrixDays()-1)
+		m.setMessage(fmt.Sprintf("Showing the last %d days", matrixRanges[m.matrixRange]), "info")
+
+	case key.Matches(msg, m.keys.Toggle):
+		m.toggleMatrixCell()
+	}
+
+	return m, nil
+}
+
+// toggleMatrixCell toggles the selected habit on the focused day.
+func (m *Model) toggleMatrixCell() {
+	habit, ok := m.selected()
+	if !ok {
+		return
+	}
+
+	day := time.Now().AddDate(0, 0, -m.matrixCol)
+	done, err := m.db.ToggleHabit(habit.ID, day.Format("2006-01-02"))
+	if err != nil {
+		m.setError(err)
+		return
+	}
+	if err := m.refresh(); err != nil {
+		m.setError(err)
+		return
+	}
+	m.selectHabit(habit.ID)
+	if err := m.loadMatrix(); err != nil {
+		m.setError(err)
+		return
+	}
+
+	if done {
+		m.setMessage(glyph("✓ ", "")+habit.Name+" done on "+day.Format("Jan 2"), "success")
+	} else {
+		m.setMessage(glyph("○ ", "")+habit.Name+" unmarked on "+day.Format("Jan 2"), "info")
+	}
+}
+
+// viewMatrix shows the list's habits against the last days, oldest on the
+// left, with each habit's streak and a marker under days where every
+// habit shown was done.
+func (m *Model) viewMatrix() string {
+	var header strings.Builder
+
+	days := m.matrixDays()
+	title := fmt.Sprintf("%sLast %d Days", glyph("📆 ", ""), days)
+	header.WriteString(titleStyle.Render(title) + "\n\n")
+
+	now := time.Now()
+	dates := make([]time.Time, days)
+	for i := range dates {
+		dates[i] = now.AddDate(0, 0, -(days - 1 - i))
+	}
+	focus := days - 1 - min(m.matrixCol, days-1)
+
+	// Column headers: weekday initial over day of month
+	pad := strings.Repeat(" ", matrixNameWidth)
+	var weekdays, dayNums strings.Builder
+	for i, d := range dates {
+		style := lipgloss.NewStyle().Foreground(theme.Label)
+		if d.Weekday() == time.Saturday || d.Weekday() == time.Sunday {
+			style = style.Foreground(theme.Weekend)
+		}
+		if i == focus {
+			style = style.Foreground(theme.Accent).Bold(true)
+		}
+		weekdays.WriteString(style.Width(matrixCellWidth).Align(lipgloss.Right).Render(d.Weekday().String()[:1]))
+		dayNums.WriteString(style.Width(matrixCellWidth).Align(lipgloss.Right).Render(d.Format("2")))
+	}
+	header.WriteString(pad + weekdays.String() + "\n")
+	header.WriteString(pad + dayNums.String() + lipgloss.NewStyle().Foreground(theme.Label).Width(matrixStreakWidth).Align(lipgloss.Right).Render("streak") + "\n")
+
+	// One line per habit row of the list
+	var lines []string
+	var lineRows []int
+	perfect := make([]bool, days)
+	for i := range perfect {
+		perfect[i] = true
+	}
+	habitsShown := 0
+
+	for i, row := range m.rows {
+		if row.header {
+			continue
+		}
+		habit := m.habits[row.habit]
+		habitsShown++
+		selected := i == m.cursor
+
+		nameStyle := normalStyle
+		marker := "  "
+		if selected {
+			nameStyle = lipgloss.NewStyle().Foreground(theme.Accent).Bold(true)
+			marker = glyph("› ", "> ")
+		}
+		name := ansi.Truncate(habit.Name, matrixNameWidth-3, glyph("…", "~"))
+		line := nameStyle.Width(matrixNameWidth).Render(marker + name)
+
+		for j, d := range dates {
+			done := m.matrixLogs[habit.ID][d.Format("2006-01-02")]
+			if !done {
+				perfect[j] = false
+			}
+
+			symbol, style := glyph("·", "."), dimStyle
+			if done {
+				symbol, style = glyph("✓", "x"), successStyle
+			}
+			cell := symbol
+			if selected && j == focus {
+				cell = "[" + symbol + "]"
+				style = style.Background(theme.Selection)
+			}
+			line += style.Width(matrixCellWidth).Align(lipgloss.Right).Render(cell)
+		}
+
+		streak := fmt.Sprintf("%d", habit.CurrentStreak)
+		line += streakStyle.Width(matrixStreakWidth).Align(lipgloss.Right).Render(streak)
+
+		lines = append(lines, line)
+		lineRows = append(lineRows, i)
+	}
+
+	// Perfect days: every habit shown was done
+	var marks strings.Builder
+	for j := range dates {
+		mark := ""
+		if perfect[j] && habitsShown > 0 {
+			mark = glyph("★", "*")
+		}
+		marks.WriteString(warningStyle.Width(matrixCellWidth).Align(lipgloss.Right).Render(mark))
+	}
+
+	var footer strings.Builder
+	footer.WriteString("\n" + dimStyle.Width(matrixNameWidth).Render("perfect") + marks.String() + "\n\n")
+
+	focusDate := dates[focus]
+	if habit, ok := m.selected(); ok {
+		status := "not done"
+		if m.matrixLogs[habit.ID][focusDate.Format("2006-01-02")] {
+			status = "done"
+		}
+		footer.WriteString(dimStyle.Render(fmt.Sprintf("%s · %s: %s", habit.Name, focusDate.Format("Mon, Jan 2"), status)) + "\n")
+	}
+	if days < matrixRanges[m.matrixRange] {
+		footer.WriteString(dimStyle.Render(fmt.Sprintf("Showing %d of %d days (window too narrow)", days, matrixRanges[m.matrixRange])) + "\n")
+	}
+	footer.WriteString("\n" + m.renderHelp())
+
+	// Whatever height is left goes to the habit rows
+	avail := 0
+	if m.height > 0 {
+		avail = m.height - strings.Count(header.String(), "\n") - lipgloss.Height(footer.String()) - 1 - boxStyle.GetVerticalFrameSize()
+		if m.message != "" {
+			avail -= 2
+		}
+	}
+
+	cursorLine := 0
+	for j, r := range lineRows {
+		if r == m.cursor {
+			cursorLine = j
+		}
+	}
+	body, shown := m.scrollList(lines, cursorLine, cursorLine+1, avail)
+
+	// Each day cell is clickable
+	top := strings.Count(header.String(), "\n")
+	for y, i := range shown {
+		if i < 0 {
+			continue
+		}
+		for j, d := range dates {
+			x := matrixNameWidth + j*matrixCellWidth
+			m.addHit(hitZone{y: top + y, x0: x, x1: x + matrixCellWidth, row: lineRows[i], date: d.Format("2006-01-02")})
+		}
+	}
+
+	return header.String() + body + "\n" + footer.String()
+}
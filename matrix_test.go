@@ -0,0 +1,57 @@
+package main
+
+import (
+	"testing"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// newTestModel opens a model on an empty database in a temporary directory.
+func newTestModel(t *testing.T) *Model {
+	t.Helper()
+	t.Chdir(t.TempDir())
+
+	keys, err := loadKeyMap(nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	m, err := NewModel(keys)
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { m.db.Close() })
+	return m
+}
+
+func TestMatrixFocusSurvivesNarrowing(t *testing.T) {
+	m := newTestModel(t)
+	if err := m.db.AddHabit("Read"); err != nil {
+		t.Fatal(err)
+	}
+	if err := m.refresh(); err != nil {
+		t.Fatal(err)
+	}
+
+	m.Update(tea.WindowSizeMsg{Width: 200, Height: 50})
+	m.openMatrix()
+	for matrixRanges[m.matrixRange] != 30 {
+		m.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("r")})
+	}
+	for range 29 {
+		m.Update(tea.KeyMsg{Type: tea.KeyLeft})
+	}
+	if m.matrixCol != 29 {
+		t.Fatalf("matrixCol = %d, want 29", m.matrixCol)
+	}
+
+	// Room for only a few day columns
+	m.Update(tea.WindowSizeMsg{Width: 50, Height: 50})
+	if days := m.matrixDays(); m.matrixCol > days-1 {
+		t.Errorf("matrixCol = %d after narrowing, want at most %d", m.matrixCol, days-1)
+	}
+	m.View()
+
+	// A stale focus must not reach the view either
+	m.matrixCol = 29
+	m.View()
+}
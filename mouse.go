@@ -0,0 +1,181 @@
+package main
+
+import (
+	"fmt"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// ============================================================
+// MOUSE
+// ============================================================
+
+// hitZone is a clickable area recorded while rendering, in lines and
+// columns from the top left of the content inside the outer box. List rows
+// carry their row index; heatmap cells carry their date and a row of -1.
+type hitZone struct {
+	y, x0, x1 int // line, and columns [x0, x1)
+	row       int
+	date      string
+}
+
+func (m *Model) addHit(z hitZone) {
+	m.hits = append(m.hits, z)
+}
+
+// hitAt maps a screen position to the zone under it. The outer box's border
+// and padding are taken off first, and when the view is taller than the
+// window the terminal shows only its bottom, so the hidden top is added back.
+func (m *Model) hitAt(x, y int) (hitZone, bool) {
+	y += max(m.viewHeight-m.height, 0) - boxStyle.GetBorderTopSize() - boxStyle.GetPaddingTop()
+	x -= boxStyle.GetBorderLeftSize() + boxStyle.GetPaddingLeft()
+
+	for _, z := range m.hits {
+		if z.y == y && x >= z.x0 && x < z.x1 {
+			return z, true
+		}
+	}
+	return hitZone{}, false
+}
+
+// updateMouse handles clicks and the scroll wheel. In the list a click
+// selects a row and a click on the selected row toggles it like enter; in
+// the heatmap, calendar and overview a click inspects a day and a second
+// click toggles it; the combined heatmap only describes the day.
+func (m *Model) updateMouse(msg tea.MouseMsg) (tea.Model, tea.Cmd) {
+	if msg.Action != tea.MouseActionPress {
+		return m, nil
+	}
+
+	switch msg.Button {
+	case tea.MouseButtonWheelUp:
+		switch m.mode {
+		case modeList, modeSearch:
+			m.cursor = max(m.cursor-1, 0)
+		case modeMatrix:
+			m.moveHabitCursor(-1)
+		case modeSummary:
+			m.summaryTop = max(m.summaryTop-1, 0)
+		}
+
+	case tea.MouseButtonWheelDown:
+		switch m.mode {
+		case modeList, modeSearch:
+			m.cursor = max(min(m.cursor+1, len(m.rows)-1), 0)
+		case modeMatrix:
+			m.moveHabitCursor(1)
+		case modeSummary:
+			m.summaryTop++
+		}
+
+	case tea.MouseButtonLeft:
+		z, ok := m.hitAt(msg.X, msg.Y)
+		if !ok {
+			break
+		}
+
+		switch {
+		case m.mode == modeList && z.row >= 0:
+			m.message = ""
+			m.err = nil
+			if z.row == m.cursor {
+				m.toggleSelected()
+			} else {
+				m.cursor = z.row
+			}
+
+		case m.mode == modeSearch && z.row >= 0:
+			m.cursor = z.row
+
+		case m.mode == modeMatrix && z.date != "":
+			m.message = ""
+			day, err := time.ParseInLocation("2006-01-02", z.date, time.Local)
+			if err != nil {
+				break
+			}
+			col := int(startOfDay(time.Now()).Sub(day).Hours()/24 + 0.5)
+			if z.row == m.cursor && col == m.matrixCol {
+				m.toggleMatrixCell()
+			} else {
+				m.cursor = z.row
+				m.matrixCol = col
+			}
+
+		case m.mode == modeCalendar && z.date != "":
+			m.message = ""
+			day, err := time.ParseInLocation("2006-01-02", z.date, time.Local)
+			if err != nil {
+				break
+			}
+			if day.Equal(m.calDay) {
+				m.toggleCalendarDay()
+			} else {
+				m.focusDay(day)
+			}
+
+		case m.mode == modeHeatmap && m.heatmapAll && z.date != "":
+			m.inspected = z.date
+			m.describeDay(z.date)
+
+		case m.mode == modeHeatmap && z.date != "":
+			if z.date == m.inspected {
+				m.toggleDate(z.date)
+			} else {
+				m.inspected = z.date
+				m.describeDate(z.date)
+			}
+		}
+	}
+
+	return m, nil
+}
+
+// describeDate shows whether the heatmap habit was done on date.
+func (m *Model) describeDate(date string) {
+	day, err := time.Parse("2006-01-02", date)
+	if err != nil {
+		return
+	}
+	label := day.Format("Mon, Jan 2 2006")
+
+	if !m.logs[date] {
+		m.setMessage(fmt.Sprintf("%s: not done (click again to mark done)", label), "info")
+		return
+	}
+	if entry, ok := m.logsWithTime[date]; ok {
+		if at, err := time.Parse("2006-01-02 15:04:05", entry.Timestamp); err == nil {
+			label += ", checked in " + at.Format("Jan 2 3:04 PM")
+		}
+	}
+	m.setMessage(label+": done (click again to unmark)", "success")
+}
+
+// toggleDate toggles the heatmap habit on date and reloads its history.
+func (m *Model) toggleDate(date string) {
+	habit, ok := m.selected()
+	if !ok {
+		return
+	}
+
+	done, err := m.db.ToggleHabit(habit.ID, date)
+	if err != nil {
+		m.setError(err)
+		return
+	}
+	if err := m.refresh(); err != nil {
+		m.setError(err)
+		return
+	}
+	m.selectHabit(habit.ID)
+	if err := m.loadHeatmap(habit.ID); err != nil {
+		m.setError(err)
+		return
+	}
+
+	if done {
+		m.setMessage(date+": marked as done", "success")
+	} else {
+		m.setMessage(date+": unmarked", "info")
+	}
+}
@@ -0,0 +1,473 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"os/exec"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// ============================================================
+// REMINDERS
+// ============================================================
+
+const (
+	reminderInterval     = 30 * time.Second
+	defaultSnoozeMinutes = 30
+	defaultStreakAlert   = "21:00"
+)
+
+type Reminder struct {
+	HabitID      int
+	HabitName    string
+	Time         string // HH:MM
+	SnoozedUntil string
+	LastNotified string
+}
+
+// parseClock parses "HH:MM" into minutes since midnight.
+func parseClock(s string) (int, error) {
+	t, err := time.Parse("15:04", strings.TrimSpace(s))
+	if err != nil {
+		return 0, fmt.Errorf("invalid time %q (expected HH:MM)", s)
+	}
+	return t.Hour()*60 + t.Minute(), nil
+}
+
+// parseClockRange parses "HH:MM-HH:MM"; the range may wrap past midnight.
+func parseClockRange(s string) (int, int, error) {
+	from, to, ok := strings.Cut(s, "-")
+	if !ok {
+		return 0, 0, fmt.Errorf("invalid range %q (expected HH:MM-HH:MM)", s)
+	}
+
+	start, err := parseClock(from)
+	if err != nil {
+		return 0, 0, err
+	}
+	end, err := parseClock(to)
+	if err != nil {
+		return 0, 0, err
+	}
+
+	return start, end, nil
+}
+
+func minutesOfDay(t time.Time) int {
+	return t.Hour()*60 + t.Minute()
+}
+
+func inQuietHours(quiet string, now time.Time) bool {
+	if quiet == "" {
+		return false
+	}
+
+	start, end, err := parseClockRange(quiet)
+	if err != nil {
+		return false
+	}
+
+	m := minutesOfDay(now)
+	if start <= end {
+		return m >= start && m < end
+	}
+	return m >= start || m < end
+}
+
+func (d *Database) SetReminder(habitID int, clock string) error {
+	minutes, err := parseClock(clock)
+	if err != nil {
+		return err
+	}
+
+	_, err = d.db.Exec(`
+		INSERT INTO reminders (habit_id, time) VALUES (?, ?)
+		ON CONFLICT(habit_id) DO UPDATE SET time = excluded.time, snoozed_until = NULL
+	`, habitID, fmt.Sprintf("%02d:%02d", minutes/60, minutes%60))
+	if err != nil {
+		return fmt.Errorf("failed to set reminder: %w", err)
+	}
+
+	return nil
+}
+
+func (d *Database) ClearReminder(habitID int) error {
+	result, err := d.db.Exec("DELETE FROM reminders WHERE habit_id = ?", habitID)
+	if err != nil {
+		return fmt.Errorf("failed to clear reminder: %w", err)
+	}
+
+	rows, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("failed to get rows affected: %w", err)
+	}
+
+	if rows == 0 {
+		return fmt.Errorf("habit has no reminder")
+	}
+
+	return nil
+}
+
+func (d *Database) SnoozeReminder(habitID int, until time.Time) error {
+	result, err := d.db.Exec("UPDATE reminders SET snoozed_until = ? WHERE habit_id = ?",
+		until.Format("2006-01-02 15:04:05"), habitID)
+	if err != nil {
+		return fmt.Errorf("failed to snooze reminder: %w", err)
+	}
+
+	rows, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("failed to get rows affected: %w", err)
+	}
+
+	if rows == 0 {
+		return fmt.Errorf("habit has no reminder")
+	}
+
+	return nil
+}
+
+func (d *Database) markReminded(habitID int, at time.Time) error {
+	_, err := d.db.Exec("UPDATE reminders SET last_notified = ? WHERE habit_id = ?",
+		at.Format("2006-01-02 15:04:05"), habitID)
+	return err
+}
+
+func (d *Database) GetReminders() ([]Reminder, error) {
+	rows, err := d.db.Query(`
+		SELECT r.habit_id, h.name, r.time, COALESCE(r.snoozed_until, ''), COALESCE(r.last_notified, '')
+		FROM reminders r
+		JOIN habits h ON h.id = r.habit_id
+		ORDER BY r.time, h.id
+	`)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get reminders: %w", err)
+	}
+	defer rows.Close()
+
+	var reminders []Reminder
+	for rows.Next() {
+		var r Reminder
+		if err := rows.Scan(&r.HabitID, &r.HabitName, &r.Time, &r.SnoozedUntil, &r.LastNotified); err != nil {
+			return nil, fmt.Errorf("failed to scan reminder: %w", err)
+		}
+		reminders = append(reminders, r)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating reminders: %w", err)
+	}
+
+	return reminders, nil
+}
+
+// getStreakAlerts returns the habits already warned about on date.
+func (d *Database) getStreakAlerts(date string) (map[int]bool, error) {
+	rows, err := d.db.Query("SELECT habit_id FROM streak_alerts WHERE date = ?", date)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get streak alerts: %w", err)
+	}
+	defer rows.Close()
+
+	alerted := make(map[int]bool)
+	for rows.Next() {
+		var id int
+		if err := rows.Scan(&id); err != nil {
+			return nil, fmt.Errorf("failed to scan streak alert: %w", err)
+		}
+		alerted[id] = true
+	}
+
+	return alerted, rows.Err()
+}
+
+func (d *Database) markStreakAlerted(habitID int, date string) error {
+	_, err := d.db.Exec(`
+		INSERT INTO streak_alerts (habit_id, date) VALUES (?, ?)
+		ON CONFLICT(habit_id) DO UPDATE SET date = excluded.date
+	`, habitID, date)
+	return err
+}
+
+// reminderDue reports whether r should fire at now for a habit not yet done
+// today. A reminder fires once a day, and once more after each snooze ends.
+func reminderDue(r Reminder, now time.Time) bool {
+	at, err := parseClock(r.Time)
+	if err != nil || minutesOfDay(now) < at {
+		return false
+	}
+
+	stamp := now.Format("2006-01-02 15:04:05")
+	if r.SnoozedUntil != "" {
+		if stamp < r.SnoozedUntil {
+			return false
+		}
+		if r.LastNotified < r.SnoozedUntil {
+			return true
+		}
+	}
+
+	return !strings.HasPrefix(r.LastNotified, now.Format("2006-01-02"))
+}
+
+type reminderDaemon struct {
+	db        *Database
+	cfg       ReminderConfig
+	refreshed string // day strength was last brought up to date
+}
+
+// check sends every notification due at now.
+func (r *reminderDaemon) check(now time.Time) error {
+	today := now.Format("2006-01-02")
+
+	// Strength decays every day without a check-in, so the daemon keeps it
+	// current for the commands that only read it
+	if r.refreshed != today {
+		if err := r.db.RefreshStrength(); err != nil {
+			return err
+		}
+		r.refreshed = today
+	}
+
+	if inQuietHours(r.cfg.QuietHours, now) {
+		return nil
+	}
+
+	done, err := r.db.GetDoneOn(today)
+	if err != nil {
+		return err
+	}
+
+	reminders, err := r.db.GetReminders()
+	if err != nil {
+		return err
+	}
+
+	for _, rem := range reminders {
+		if done[rem.HabitID] || !reminderDue(rem, now) {
+			continue
+		}
+
+		if !r.send("⏰ Habit reminder", fmt.Sprintf("Time for: %s", rem.HabitName)) {
+			continue
+		}
+		if err := r.db.markReminded(rem.HabitID, now); err != nil {
+			return fmt.Errorf("failed to record reminder: %w", err)
+		}
+	}
+
+	alertAt := defaultStreakAlert
+	if r.cfg.StreakAlert != "" {
+		alertAt = r.cfg.StreakAlert
+	}
+	alertMinutes, err := parseClock(alertAt)
+	if err != nil {
+		return err
+	}
+	if minutesOfDay(now) < alertMinutes {
+		return nil
+	}
+
+	// A streak is only at risk if it is still alive, i.e. yesterday was done.
+	yesterday, err := r.db.GetDoneOn(now.AddDate(0, 0, -1).Format("2006-01-02"))
+	if err != nil {
+		return err
+	}
+
+	alerted, err := r.db.getStreakAlerts(today)
+	if err != nil {
+		return err
+	}
+
+	habits, err := r.db.GetHabits()
+	if err != nil {
+		return err
+	}
+
+	for _, h := range habits {
+		if h.CurrentStreak == 0 || done[h.ID] || !yesterday[h.ID] || alerted[h.ID] {
+			continue
+		}
+
+		body := fmt.Sprintf("%s: your %d day streak ends at midnight", h.Name, h.CurrentStreak)
+		if !r.send("🔥 Streak at risk", body) {
+			continue
+		}
+		if err := r.db.markStreakAlerted(h.ID, today); err != nil {
+			return fmt.Errorf("failed to record streak alert: %w", err)
+		}
+	}
+
+	// Goals at risk are warned about at the same time, once a day each
+	risky, byID, err := r.db.goalsAtRisk(now)
+	if err != nil {
+		return err
+	}
+
+	goalsAlerted, err := r.db.getGoalAlerts(today)
+	if err != nil {
+		return err
+	}
+
+	for _, g := range risky {
+		if goalsAlerted[g.ID] {
+			continue
+		}
+
+		body := fmt.Sprintf("%s: %s is falling behind", byID[g.HabitID].Name, g)
+		if !r.send("🎯 Goal at risk", body) {
+			continue
+		}
+		if err := r.db.markGoalAlerted(g.ID, today); err != nil {
+			return fmt.Errorf("failed to record goal alert: %w", err)
+		}
+	}
+
+	return nil
+}
+
+// send notifies and reports whether it worked. A failure is logged rather
+// than returned so that it does not hold up the other notifications; the
+// one that failed is tried again on the next check.
+func (r *reminderDaemon) send(title, body string) bool {
+	if err := r.notify(title, body); err != nil {
+		fmt.Printf("Error: %v\n", err)
+		return false
+	}
+	return true
+}
+
+func (r *reminderDaemon) notify(title, body string) error {
+	command := r.cfg.Command
+	if len(command) == 0 {
+		command = []string{"notify-send", "--app-name=Habit Tracker"}
+	}
+
+	args := append(append([]string{}, command[1:]...), title, body)
+	if out, err := exec.Command(command[0], args...).CombinedOutput(); err != nil {
+		return fmt.Errorf("notification command failed: %v: %s", err, strings.TrimSpace(string(out)))
+	}
+
+	return nil
+}
+
+// runRemind implements `habit remind` and its set/clear/snooze/list commands.
+func runRemind(args []string) error {
+	cfg, err := loadConfig()
+	if err != nil {
+		return err
+	}
+
+	db, err := NewDatabase()
+	if err != nil {
+		return err
+	}
+	defer db.Close()
+
+	if len(args) > 0 && !strings.HasPrefix(args[0], "-") {
+		return runRemindCommand(db, cfg.Reminders, args[0], args[1:])
+	}
+
+	fset := flag.NewFlagSet("remind", flag.ContinueOnError)
+	once := fset.Bool("once", false, "check once and exit (for cron)")
+	if err := fset.Parse(args); err != nil {
+		return err
+	}
+
+	if err := db.RecordAchievements(); err != nil {
+		return err
+	}
+
+	daemon := &reminderDaemon{db: db, cfg: cfg.Reminders}
+	if *once {
+		return daemon.check(time.Now())
+	}
+
+	ticker := time.NewTicker(reminderInterval)
+	defer ticker.Stop()
+
+	for {
+		if err := daemon.check(time.Now()); err != nil {
+			fmt.Printf("Error: %v\n", err)
+		}
+		<-ticker.C
+	}
+}
+
+func runRemindCommand(db *Database, cfg ReminderConfig, name string, args []string) error {
+	switch name {
+	case "list":
+		reminders, err := db.GetReminders()
+		if err != nil {
+			return err
+		}
+		if len(reminders) == 0 {
+			fmt.Println("No reminders set")
+		}
+		for _, r := range reminders {
+			line := fmt.Sprintf("%s  %s", r.Time, r.HabitName)
+			if r.SnoozedUntil > time.Now().Format("2006-01-02 15:04:05") {
+				line += fmt.Sprintf("  (snoozed until %s)", r.SnoozedUntil[11:16])
+			}
+			fmt.Println(line)
+		}
+		return nil
+
+	case "set":
+		if len(args) != 2 {
+			return fmt.Errorf("usage: habit remind set <habit> HH:MM")
+		}
+		habit, err := db.FindHabit(args[0])
+		if err != nil {
+			return err
+		}
+		if err := db.SetReminder(habit.ID, args[1]); err != nil {
+			return err
+		}
+		fmt.Printf("Reminder for %s set to %s\n", habit.Name, args[1])
+		return nil
+
+	case "clear":
+		if len(args) != 1 {
+			return fmt.Errorf("usage: habit remind clear <habit>")
+		}
+		habit, err := db.FindHabit(args[0])
+		if err != nil {
+			return err
+		}
+		return db.ClearReminder(habit.ID)
+
+	case "snooze":
+		if len(args) < 1 || len(args) > 2 {
+			return fmt.Errorf("usage: habit remind snooze <habit> [minutes]")
+		}
+		habit, err := db.FindHabit(args[0])
+		if err != nil {
+			return err
+		}
+
+		minutes := cfg.Snooze
+		if minutes <= 0 {
+			minutes = defaultSnoozeMinutes
+		}
+		if len(args) == 2 {
+			minutes, err = strconv.Atoi(args[1])
+			if err != nil || minutes <= 0 {
+				return fmt.Errorf("invalid snooze minutes %q", args[1])
+			}
+		}
+
+		until := time.Now().Add(time.Duration(minutes) * time.Minute)
+		if err := db.SnoozeReminder(habit.ID, until); err != nil {
+			return err
+		}
+		fmt.Printf("Reminder for %s snoozed until %s\n", habit.Name, until.Format("15:04"))
+		return nil
+
+	default:
+		return fmt.Errorf("unknown remind command %q", name)
+	}
+}
@@ -0,0 +1,165 @@
+package main
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestReminderDue(t *testing.T) {
+	at := func(clock string) time.Time {
+		t, err := time.ParseInLocation("2006-01-02 15:04", "2026-10-18 "+clock, time.Local)
+		if err != nil {
+			panic(err)
+		}
+		return t
+	}
+
+	tests := []struct {
+		name     string
+		reminder Reminder
+		now      time.Time
+		want     bool
+	}{
+		{name: "before its time", reminder: Reminder{Time: "08:00"}, now: at("07:59"), want: false},
+		{name: "at its time", reminder: Reminder{Time: "08:00"}, now: at("08:00"), want: true},
+		{name: "later the same day", reminder: Reminder{Time: "08:00"}, now: at("21:00"), want: true},
+		{
+			name:     "already sent today",
+			reminder: Reminder{Time: "08:00", LastNotified: "2026-10-18 08:00:10"},
+			now:      at("12:00"),
+			want:     false,
+		},
+		{
+			name:     "sent yesterday",
+			reminder: Reminder{Time: "08:00", LastNotified: "2026-10-17 08:00:10"},
+			now:      at("08:00"),
+			want:     true,
+		},
+		{
+			name:     "snoozed",
+			reminder: Reminder{Time: "08:00", LastNotified: "2026-10-18 08:00:10", SnoozedUntil: "2026-10-18 08:30:10"},
+			now:      at("08:30"),
+			want:     false,
+		},
+		{
+			name:     "snooze over",
+			reminder: Reminder{Time: "08:00", LastNotified: "2026-10-18 08:00:10", SnoozedUntil: "2026-10-18 08:30:10"},
+			now:      at("08:31"),
+			want:     true,
+		},
+		{
+			name:     "sent again after the snooze",
+			reminder: Reminder{Time: "08:00", LastNotified: "2026-10-18 08:31:00", SnoozedUntil: "2026-10-18 08:30:10"},
+			now:      at("09:00"),
+			want:     false,
+		},
+		{
+			name:     "snoozed from yesterday and sent after it",
+			reminder: Reminder{Time: "08:00", LastNotified: "2026-10-17 22:31:00", SnoozedUntil: "2026-10-17 22:30:10"},
+			now:      at("08:00"),
+			want:     true,
+		},
+		{
+			name:     "snoozed past its time today",
+			reminder: Reminder{Time: "08:00", LastNotified: "2026-10-17 23:50:00", SnoozedUntil: "2026-10-18 09:00:00"},
+			now:      at("08:30"),
+			want:     false,
+		},
+		{name: "invalid time", reminder: Reminder{Time: "8am"}, now: at("12:00"), want: false},
+	}
+
+	for _, tt := range tests {
+		if got := reminderDue(tt.reminder, tt.now); got != tt.want {
+			t.Errorf("%s: reminderDue = %v, want %v", tt.name, got, tt.want)
+		}
+	}
+}
+
+func TestInQuietHours(t *testing.T) {
+	tests := []struct {
+		quiet string
+		clock string
+		want  bool
+	}{
+		{quiet: "", clock: "03:00", want: false},
+		{quiet: "13:00-14:00", clock: "12:59", want: false},
+		{quiet: "13:00-14:00", clock: "13:00", want: true},
+		{quiet: "13:00-14:00", clock: "13:59", want: true},
+		{quiet: "13:00-14:00", clock: "14:00", want: false},
+
+		// Ranges that wrap past midnight
+		{quiet: "22:00-07:00", clock: "21:59", want: false},
+		{quiet: "22:00-07:00", clock: "22:00", want: true},
+		{quiet: "22:00-07:00", clock: "23:59", want: true},
+		{quiet: "22:00-07:00", clock: "00:00", want: true},
+		{quiet: "22:00-07:00", clock: "06:59", want: true},
+		{quiet: "22:00-07:00", clock: "07:00", want: false},
+		{quiet: "22:00-07:00", clock: "12:00", want: false},
+
+		{quiet: "08:00-08:00", clock: "08:00", want: false},
+		{quiet: " 22:00 - 07:00 ", clock: "23:00", want: true},
+		{quiet: "22:00", clock: "23:00", want: false},
+		{quiet: "late-early", clock: "23:00", want: false},
+	}
+
+	for _, tt := range tests {
+		now, err := time.ParseInLocation("2006-01-02 15:04", "2026-10-18 "+tt.clock, time.Local)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if got := inQuietHours(tt.quiet, now); got != tt.want {
+			t.Errorf("inQuietHours(%q) at %s = %v, want %v", tt.quiet, tt.clock, got, tt.want)
+		}
+	}
+}
+
+func TestReminderCheckSurvivesFailedNotification(t *testing.T) {
+	db := newTestDatabase(t)
+	for _, name := range []string{"Read", "Walk"} {
+		if err := db.AddHabit(name); err != nil {
+			t.Fatal(err)
+		}
+	}
+	habits, err := db.GetHabits()
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, h := range habits {
+		if err := db.SetReminder(h.ID, "08:00"); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	// The notifier fails for Read and records the rest
+	sent := filepath.Join(t.TempDir(), "sent")
+	script := `case "$2" in *Read*) echo no display >&2; exit 1;; esac; echo "$2" >> "$SENT"`
+	t.Setenv("SENT", sent)
+	daemon := &reminderDaemon{db: db, cfg: ReminderConfig{Command: []string{"sh", "-c", script, "notify"}}}
+
+	now := time.Date(2026, 10, 18, 9, 0, 0, 0, time.Local)
+	if err := daemon.check(now); err != nil {
+		t.Fatalf("check: %v", err)
+	}
+
+	out, err := os.ReadFile(sent)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := strings.TrimSpace(string(out)); got != "Time for: Walk" {
+		t.Errorf("sent %q, want only Walk's reminder", got)
+	}
+
+	reminders, err := db.GetReminders()
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, r := range reminders {
+		// Read's reminder failed, so it is still due next time
+		if want := r.HabitName == "Read"; reminderDue(r, now) != want {
+			t.Errorf("%s reminder due again = %v, want %v", r.HabitName, !want, want)
+		}
+	}
+}
@@ -0,0 +1,401 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	htmltemplate "html/template"
+	"io"
+	"os"
+	"sort"
+	"strings"
+	"text/template"
+	"time"
+)
+
+// ============================================================
+// REPORTS
+// ============================================================
+
+type habitReport struct {
+	Habit        Habit
+	Days         int // days in the period the habit existed
+	Completed    int
+	Rate         float64
+	BestStreak   int // longest streak within the period
+	Before       habitStats
+	After        habitStats
+	Achievements []string
+	Heatmap      []reportHeatmapRow
+}
+
+type reportHeatmapRow struct {
+	Label string
+	Cells []reportHeatmapCell
+}
+
+type reportHeatmapCell struct {
+	Date   string
+	Done   bool
+	Active bool // inside the period
+}
+
+type report struct {
+	Period    string
+	Start     time.Time
+	End       time.Time
+	Habits    []habitReport
+	Global    []string // achievements unlocked by all habits together
+	Days      int
+	Completed int
+	XPGained  int
+	Unlocked  int
+}
+
+func (r report) Rate() float64 {
+	if r.Days == 0 {
+		return 0
+	}
+	return float64(r.Completed) / float64(r.Days) * 100
+}
+
+func (h habitReport) StreakChange() int { return h.After.Streak - h.Before.Streak }
+func (h habitReport) XPGained() int     { return h.After.XP - h.Before.XP }
+
+// reportStart returns the first day of the period ending on end.
+func reportStart(period string, end time.Time) (time.Time, error) {
+	switch period {
+	case "week":
+		return end.AddDate(0, 0, -6), nil
+	case "month":
+		return end.AddDate(0, -1, 1), nil
+	case "year":
+		return end.AddDate(-1, 0, 1), nil
+	default:
+		return time.Time{}, fmt.Errorf("unknown period %q (expected week, month or year)", period)
+	}
+}
+
+// GetAchievementsBetween returns achievement types unlocked per habit between
+// two local dates (inclusive), with those earned by all habits together
+// under 0.
+func (d *Database) GetAchievementsBetween(start, end string) (map[int][]string, error) {
+	// unlocked_at is stored in UTC
+	rows, err := d.db.Query(`
+		SELECT habit_id, type, unlocked_at FROM achievements
+		WHERE date(unlocked_at, 'localtime') BETWEEN ?1 AND ?2
+		UNION ALL
+		SELECT 0, type, unlocked_at FROM global_achievements
+		WHERE date(unlocked_at, 'localtime') BETWEEN ?1 AND ?2
+		ORDER BY unlocked_at
+	`, start, end)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get achievements: %w", err)
+	}
+	defer rows.Close()
+
+	unlocked := make(map[int][]string)
+	for rows.Next() {
+		var id int
+		var t, at string
+		if err := rows.Scan(&id, &t, &at); err != nil {
+			return nil, fmt.Errorf("failed to scan achievement: %w", err)
+		}
+		unlocked[id] = append(unlocked[id], t)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating achievements: %w", err)
+	}
+
+	return unlocked, nil
+}
+
+func buildReport(db *Database, period string, now time.Time) (report, error) {
+	start, err := reportStart(period, now)
+	if err != nil {
+		return report{}, err
+	}
+
+	r := report{Period: period, Start: start, End: now}
+	startStr := start.Format("2006-01-02")
+	endStr := now.Format("2006-01-02")
+
+	habits, err := db.GetHabits()
+	if err != nil {
+		return r, err
+	}
+
+	allLogs, err := db.GetAllLogs()
+	if err != nil {
+		return r, err
+	}
+	datesByHabit := make(map[int][]string)
+	for _, l := range allLogs {
+		datesByHabit[l.HabitID] = append(datesByHabit[l.HabitID], l.Date)
+	}
+
+	unlocked, err := db.GetAchievementsBetween(startStr, endStr)
+	if err != nil {
+		return r, err
+	}
+
+	totalDays := int(now.Sub(start).Hours()/24) + 1
+	weeks := (totalDays + 6) / 7
+
+	for _, h := range habits {
+		dates := datesByHabit[h.ID]
+		sort.Sort(sort.Reverse(sort.StringSlice(dates)))
+
+		// Stats at the end of the day before the period, and at its end
+		dayBefore := start.AddDate(0, 0, -1)
+		before, err := computeStats(datesUpTo(dates, dayBefore.Format("2006-01-02")), dayBefore)
+		if err != nil {
+			return r, err
+		}
+		after, err := computeStats(datesUpTo(dates, endStr), now)
+		if err != nil {
+			return r, err
+		}
+
+		inPeriod := make(map[string]bool)
+		for _, d := range dates {
+			if d >= startStr && d <= endStr {
+				inPeriod[d] = true
+			}
+		}
+
+		// Only count days since the habit was created
+		habitStart := start
+		if created, err := time.Parse("2006-01-02 15:04:05", h.CreatedAt); err == nil && created.After(start) {
+			habitStart = time.Date(created.Year(), created.Month(), created.Day(), 0, 0, 0, 0, now.Location())
+		}
+		days, rate := completionStats(inPeriod, habitStart, now, int(now.Sub(habitStart).Hours()/24)+1)
+
+		hr := habitReport{
+			Habit:      h,
+			Days:       days,
+			Completed:  len(inPeriod),
+			Rate:       rate,
+			BestStreak: calculateBestStreak(inPeriod),
+			Before:     before,
+			After:      after,
+			Heatmap:    reportHeatmap(inPeriod, weeks, startStr, now),
+		}
+		for _, t := range unlocked[h.ID] {
+			hr.Achievements = append(hr.Achievements, achievementLabel(t))
+		}
+
+		r.Habits = append(r.Habits, hr)
+		r.Days += hr.Days
+		r.Completed += hr.Completed
+		r.XPGained += hr.XPGained()
+		r.Unlocked += len(hr.Achievements)
+	}
+
+	for _, t := range unlocked[0] {
+		r.Global = append(r.Global, achievementLabel(t))
+	}
+	r.Unlocked += len(r.Global)
+
+	return r, nil
+}
+
+// datesUpTo filters newest-first dates to those on or before last.
+func datesUpTo(dates []string, last string) []string {
+	for i, d := range dates {
+		if d <= last {
+			return dates[i:]
+		}
+	}
+	return nil
+}
+
+func reportHeatmap(logs map[string]bool, weeks int, startStr string, end time.Time) []reportHeatmapRow {
+	days := []string{"Sun", "Mon", "Tue", "Wed", "Thu", "Fri", "Sat"}
+	cells := heatmapCells(logs, weeks, end)
+
+	rows := make([]reportHeatmapRow, 7)
+	for day := range cells {
+		rows[day].Label = days[day]
+		for _, c := range cells[day] {
+			rows[day].Cells = append(rows[day].Cells, reportHeatmapCell{
+				Date:   c.Date,
+				Done:   c.Done,
+				Active: c.InRange && c.Date >= startStr,
+			})
+		}
+	}
+	return rows
+}
+
+var reportFuncs = map[string]any{
+	"date":   func(t time.Time) string { return t.Format("Jan 2, 2006") },
+	"signed": func(n int) string { return fmt.Sprintf("%+d", n) },
+	"title":  func(s string) string { return strings.ToUpper(s[:1]) + s[1:] },
+	"join":   strings.Join,
+	// md keeps text on one line and out of table syntax: a "|" would end
+	// the cell and a newline the row.
+	"md": strings.NewReplacer(`|`, `\|`, "\r\n", " ", "\n", " ", "\r", " ").Replace,
+	"textCell": func(c reportHeatmapCell) string {
+		switch {
+		case !c.Active:
+			return "  "
+		case c.Done:
+			return "██"
+		default:
+			return "░░"
+		}
+	},
+}
+
+const markdownReport = `# {{title .Period}}ly Habit Report
+
+{{date .Start}} – {{date .End}}
+
+**{{.Completed}}/{{.Days}} check-ins ({{printf "%.1f" .Rate}}%)** · **{{signed .XPGained}} XP** · **{{.Unlocked}} achievements unlocked**
+
+| Habit | Completion | Streak | XP | Level |
+|-------|------------|--------|----|-------|
+{{- range .Habits}}
+| {{md .Habit.Name}} | {{.Completed}}/{{.Days}} ({{printf "%.1f" .Rate}}%) | {{.Before.Streak}} → {{.After.Streak}} ({{signed .StreakChange}}) | {{signed .XPGained}} | {{.Before.Level}} → {{.After.Level}} |
+{{- end}}
+{{- if .Global}}
+
+Unlocked by all habits together: {{md (join .Global ", ")}}
+{{- end}}
+{{range .Habits}}
+## {{md .Habit.Name}}
+
+- Completion: {{.Completed}}/{{.Days}} days ({{printf "%.1f" .Rate}}%)
+- Streak: {{.Before.Streak}} → {{.After.Streak}} days ({{signed .StreakChange}}), best in period {{.BestStreak}} days
+- XP: {{.Before.XP}} → {{.After.XP}} ({{signed .XPGained}}), level {{.Before.Level}} → {{.After.Level}}
+{{- if .Achievements}}
+- Achievements unlocked: {{md (join .Achievements ", ")}}
+{{- end}}
+
+` + "```" + `
+{{range .Heatmap}}{{.Label}} {{range .Cells}}{{textCell .}}{{end}}
+{{end}}` + "```" + `
+{{end}}`
+
+const htmlReport = `<!DOCTYPE html>
+<html lang="en">
+<head>
+<meta charset="utf-8">
+<title>{{title .Period}}ly Habit Report</title>
+<style>
+  body { background: #0D1117; color: #FAFAFA; font-family: ui-monospace, SFMono-Regular, Menlo, Consolas, monospace; padding: 2rem; }
+  h1, h2 { color: #7D56F4; }
+  table.summary { border-collapse: collapse; margin-bottom: 2rem; }
+  table.summary th, table.summary td { border: 1px solid #3C3C3C; padding: 0.3rem 0.8rem; text-align: left; }
+  table.summary th { color: #AAAAAA; }
+  .dim { color: #626262; }
+  .up { color: #39D353; }
+  .down { color: #FF5F87; }
+  table.heatmap { border-collapse: separate; border-spacing: 2px; }
+  table.heatmap th { color: #AAAAAA; font-weight: normal; padding-right: 0.5rem; font-size: 0.8rem; }
+  table.heatmap td { width: 10px; height: 10px; border-radius: 2px; padding: 0; }
+  td.none { background: #161B22; }
+  td.done { background: #39D353; }
+</style>
+</head>
+<body>
+<h1>{{title .Period}}ly Habit Report</h1>
+<p class="dim">{{date .Start}} – {{date .End}}</p>
+<p><strong>{{.Completed}}/{{.Days}} check-ins ({{printf "%.1f" .Rate}}%)</strong> · <strong>{{signed .XPGained}} XP</strong> · <strong>{{.Unlocked}} achievements unlocked</strong></p>
+
+<table class="summary">
+<tr><th>Habit</th><th>Completion</th><th>Streak</th><th>XP</th><th>Level</th></tr>
+{{- range .Habits}}
+<tr>
+  <td>{{.Habit.Name}}</td>
+  <td>{{.Completed}}/{{.Days}} ({{printf "%.1f" .Rate}}%)</td>
+  <td>{{.Before.Streak}} → {{.After.Streak}} <span class="{{if lt .StreakChange 0}}down{{else}}up{{end}}">({{signed .StreakChange}})</span></td>
+  <td>{{signed .XPGained}}</td>
+  <td>{{.Before.Level}} → {{.After.Level}}</td>
+</tr>
+{{- end}}
+</table>
+{{- if .Global}}
+<p>Unlocked by all habits together: {{join .Global ", "}}</p>
+{{- end}}
+{{range .Habits}}
+<h2>{{.Habit.Name}}</h2>
+<ul>
+  <li>Completion: {{.Completed}}/{{.Days}} days ({{printf "%.1f" .Rate}}%)</li>
+  <li>Streak: {{.Before.Streak}} → {{.After.Streak}} days ({{signed .StreakChange}}), best in period {{.BestStreak}} days</li>
+  <li>XP: {{.Before.XP}} → {{.After.XP}} ({{signed .XPGained}}), level {{.Before.Level}} → {{.After.Level}}</li>
+  {{- if .Achievements}}
+  <li>Achievements unlocked: {{join .Achievements ", "}}</li>
+  {{- end}}
+</ul>
+<table class="heatmap">
+{{- range .Heatmap}}
+<tr><th>{{.Label}}</th>{{range .Cells}}{{if .Active}}<td class="{{if .Done}}done{{else}}none{{end}}" title="{{.Date}}"></td>{{else}}<td></td>{{end}}{{end}}</tr>
+{{- end}}
+</table>
+{{end}}
+</body>
+</html>
+`
+
+func writeReport(w io.Writer, r report, format string) error {
+	switch format {
+	case "md":
+		tmpl := template.Must(template.New("report").Funcs(reportFuncs).Parse(markdownReport))
+		return tmpl.Execute(w, r)
+	case "html":
+		tmpl := htmltemplate.Must(htmltemplate.New("report").Funcs(reportFuncs).Parse(htmlReport))
+		return tmpl.Execute(w, r)
+	default:
+		return fmt.Errorf("unsupported report format %q (expected md or html)", format)
+	}
+}
+
+// runReport implements `habit report`.
+func runReport(args []string) error {
+	fset := flag.NewFlagSet("report", flag.ContinueOnError)
+	period := fset.String("period", "week", "report period: week, month or year")
+	format := fset.String("format", "md", "output format: md or html")
+	output := fset.String("o", "-", "output file, - for stdout")
+	if err := fset.Parse(args); err != nil {
+		return err
+	}
+
+	if *format != "md" && *format != "html" {
+		return fmt.Errorf("unsupported report format %q (expected md or html)", *format)
+	}
+
+	// For the names of configured achievements
+	if _, err := loadConfig(); err != nil {
+		return err
+	}
+
+	db, err := NewDatabase()
+	if err != nil {
+		return err
+	}
+	defer db.Close()
+
+	r, err := buildReport(db, *period, time.Now())
+	if err != nil {
+		return err
+	}
+
+	if *output == "-" {
+		return writeReport(os.Stdout, r, *format)
+	}
+
+	f, err := os.Create(*output)
+	if err != nil {
+		return fmt.Errorf("failed to create %s: %w", *output, err)
+	}
+	if err := writeReport(f, r, *format); err != nil {
+		f.Close()
+		return err
+	}
+	if err := f.Close(); err != nil {
+		return fmt.Errorf("failed to write %s: %w", *output, err)
+	}
+	return nil
+}
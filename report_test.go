@@ -0,0 +1,78 @@
+package main
+
+import (
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestMarkdownReportEscapesNames(t *testing.T) {
+	end := time.Date(2026, 10, 18, 0, 0, 0, 0, time.Local)
+	r := report{
+		Period: "week",
+		Start:  end.AddDate(0, 0, -6),
+		End:    end,
+		Habits: []habitReport{{Habit: Habit{Name: "Read | write\ndaily"}, Days: 7}},
+		Days:   7,
+	}
+
+	var b strings.Builder
+	if err := writeReport(&b, r, "md"); err != nil {
+		t.Fatal(err)
+	}
+	out := b.String()
+
+	row := `| Read \| write daily | 0/7 (0.0%) |`
+	if !strings.Contains(out, row) {
+		t.Errorf("summary table has no row starting %q:\n%s", row, out)
+	}
+	if heading := `## Read \| write daily` + "\n"; !strings.Contains(out, heading) {
+		t.Errorf("report has no heading %q:\n%s", heading, out)
+	}
+}
+
+func TestReportIncludesGlobalAchievements(t *testing.T) {
+	db := newTestDatabase(t)
+	if err := db.AddHabit("Read"); err != nil {
+		t.Fatal(err)
+	}
+	habits, err := db.GetHabits()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// unlocked_at is UTC, as CURRENT_TIMESTAMP stores it
+	now := time.Now()
+	stamp := func(t time.Time) string { return t.UTC().Format("2006-01-02 15:04:05") }
+	for _, q := range []struct {
+		sql  string
+		args []any
+	}{
+		{"INSERT INTO achievements (habit_id, type, unlocked_at) VALUES (?, ?, ?)", []any{habits[0].ID, "streak_3", stamp(now)}},
+		{"INSERT INTO global_achievements (type, unlocked_at) VALUES (?, ?)", []any{"together", stamp(now)}},
+		{"INSERT INTO global_achievements (type, unlocked_at) VALUES (?, ?)", []any{"long_ago", stamp(now.AddDate(0, -2, 0))}},
+	} {
+		if _, err := db.db.Exec(q.sql, q.args...); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	r, err := buildReport(db, "week", now)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(r.Global) != 1 || r.Global[0] != "together" {
+		t.Errorf("Global = %v, want [together]", r.Global)
+	}
+	if r.Unlocked != 2 {
+		t.Errorf("Unlocked = %d, want 2", r.Unlocked)
+	}
+
+	var b strings.Builder
+	if err := writeReport(&b, r, "md"); err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(b.String(), "Unlocked by all habits together: together\n") {
+		t.Errorf("report does not list the global achievement:\n%s", b.String())
+	}
+}
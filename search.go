@@ -0,0 +1,187 @@
+package main
+
+import (
+	"strings"
+	"unicode"
+
+	"github.com/charmbracelet/bubbles/key"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+)
+
+// ============================================================
+// SEARCH
+// ============================================================
+
+// fuzzyMatch reports whether the runes of pattern appear in text in order,
+// ignoring case and spaces, and returns the rune positions that matched.
+// Matches at word starts are preferred when they still allow a full match.
+func fuzzyMatch(pattern, text string) ([]int, bool) {
+	var want []rune
+	for _, r := range strings.ToLower(pattern) {
+		if !unicode.IsSpace(r) {
+			want = append(want, r)
+		}
+	}
+	if len(want) == 0 {
+		return nil, true
+	}
+
+	runes := []rune(strings.ToLower(text))
+	if positions, ok := matchRunes(want, runes, true); ok {
+		return positions, true
+	}
+	return matchRunes(want, runes, false)
+}
+
+func matchRunes(want, runes []rune, preferWordStart bool) ([]int, bool) {
+	var positions []int
+	next := 0
+	for _, r := range want {
+		found := -1
+		for i := next; i < len(runes); i++ {
+			if runes[i] != r {
+				continue
+			}
+			if found < 0 {
+				found = i
+				if !preferWordStart {
+					break
+				}
+			}
+			if i == 0 || !unicode.IsLetter(runes[i-1]) && !unicode.IsDigit(runes[i-1]) {
+				found = i
+				break
+			}
+		}
+		if found < 0 {
+			return nil, false
+		}
+		positions = append(positions, found)
+		next = found + 1
+	}
+	return positions, true
+}
+
+// searchMatch matches the active search against a habit's name, then its
+// tags. Positions are only returned for name matches.
+func (m *Model) searchMatch(h Habit) ([]int, bool) {
+	if m.search == "" {
+		return nil, true
+	}
+	if positions, ok := fuzzyMatch(m.search, h.Name); ok {
+		return positions, true
+	}
+	for _, tag := range h.Tags {
+		if _, ok := fuzzyMatch(m.search, tag); ok {
+			return nil, true
+		}
+	}
+	return nil, false
+}
+
+// highlight renders text in base style with the runes at positions picked
+// out in the match color.
+func highlight(text string, positions []int, base lipgloss.Style) string {
+	if len(positions) == 0 {
+		return base.Render(text)
+	}
+
+	hl := base.Foreground(theme.Highlight).Underline(true)
+	matched := make(map[int]bool, len(positions))
+	for _, p := range positions {
+		matched[p] = true
+	}
+
+	var b strings.Builder
+	var run []rune
+	runMatched := false
+	flush := func() {
+		if len(run) == 0 {
+			return
+		}
+		if runMatched {
+			b.WriteString(hl.Render(string(run)))
+		} else {
+			b.WriteString(base.Render(string(run)))
+		}
+		run = run[:0]
+	}
+	for i, r := range []rune(text) {
+		if matched[i] != runMatched {
+			flush()
+			runMatched = matched[i]
+		}
+		run = append(run, r)
+	}
+	flush()
+
+	return b.String()
+}
+
+// startSearch opens the search prompt, keeping any previous query.
+func (m *Model) startSearch() {
+	m.mode = modeSearch
+	m.input.Placeholder = "Search habits and tags..."
+	m.input.CharLimit = maxHabitName
+	m.input.SetValue(m.search)
+	m.input.CursorEnd()
+	m.input.Focus()
+}
+
+// setSearch applies a new query and moves the cursor to the first match
+// if the selected habit no longer matches.
+func (m *Model) setSearch(query string) {
+	m.search = strings.TrimSpace(query)
+	m.rebuildRows()
+	if _, ok := m.selected(); ok {
+		return
+	}
+	for i, row := range m.rows {
+		if !row.header {
+			m.cursor = i
+			return
+		}
+	}
+}
+
+// updateSearch filters the list as the query is typed. The arrow keys move
+// through the matches, enter keeps the filter and returns to the list so
+// the usual keys act on the filtered habits, and esc clears it.
+func (m *Model) updateSearch(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	switch {
+	case key.Matches(msg, m.keys.Cancel):
+		m.mode = modeList
+		m.input.Blur()
+		m.setSearch("")
+		return m, nil
+
+	case key.Matches(msg, m.keys.Submit):
+		m.mode = modeList
+		m.input.Blur()
+		if m.search != "" && len(m.rows) == 0 {
+			m.setSearch("")
+			m.setMessage("No matches", "info")
+		}
+		return m, nil
+
+	case key.Matches(msg, m.keys.PrevMatch):
+		if m.cursor > 0 {
+			m.cursor--
+		}
+		return m, nil
+
+	case key.Matches(msg, m.keys.NextMatch):
+		if m.cursor < len(m.rows)-1 {
+			m.cursor++
+		}
+		return m, nil
+	}
+
+	var cmd tea.Cmd
+	m.input, cmd = m.input.Update(msg)
+	if strings.TrimSpace(m.input.Value()) != m.search {
+		m.setSearch(m.input.Value())
+	}
+	return m, cmd
+}
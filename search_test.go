@@ -0,0 +1,62 @@
+package main
+
+import (
+	"slices"
+	"testing"
+	"unicode"
+)
+
+func TestFuzzyMatch(t *testing.T) {
+	tests := []struct {
+		pattern, text string
+		want          []int
+		wantOK        bool
+	}{
+		{pattern: "", text: "Read", want: nil, wantOK: true},
+		{pattern: "rd", text: "Read", want: []int{0, 3}, wantOK: true},
+		{pattern: "read", text: "Read", want: []int{0, 1, 2, 3}, wantOK: true},
+
+		// Word starts win over earlier letters
+		{pattern: "w", text: "Slow walk", want: []int{5}, wantOK: true},
+		{pattern: "sw", text: "Slow walk", want: []int{0, 5}, wantOK: true},
+		{pattern: "5k", text: "Run 5k", want: []int{4, 5}, wantOK: true},
+		// ...unless taking them leaves the rest unmatched
+		{pattern: "ab", text: "cab a", want: []int{1, 2}, wantOK: true},
+
+		// Case and spaces are ignored
+		{pattern: "READ", text: "read", want: []int{0, 1, 2, 3}, wantOK: true},
+		{pattern: "r b", text: "Read Books", want: []int{0, 5}, wantOK: true},
+
+		// Positions are runes of the original text
+		{pattern: "é", text: "Café", want: []int{3}, wantOK: true},
+		{pattern: "CAFÉ", text: "café", want: []int{0, 1, 2, 3}, wantOK: true},
+		{pattern: "ist", text: "İstanbul walk", want: []int{0, 1, 2}, wantOK: true},
+		{pattern: "wa", text: "İİ walk", want: []int{3, 4}, wantOK: true},
+
+		{pattern: "xyz", text: "Read", wantOK: false},
+		{pattern: "dr", text: "Read", wantOK: false},
+		{pattern: "reads", text: "Read", wantOK: false},
+	}
+
+	for _, tt := range tests {
+		got, ok := fuzzyMatch(tt.pattern, tt.text)
+		if ok != tt.wantOK || !slices.Equal(got, tt.want) {
+			t.Errorf("fuzzyMatch(%q, %q) = %v, %v; want %v, %v", tt.pattern, tt.text, got, ok, tt.want, tt.wantOK)
+			continue
+		}
+
+		// Each position must pick out the matching rune of the text as shown
+		text := []rune(tt.text)
+		var want []rune
+		for _, r := range tt.pattern {
+			if !unicode.IsSpace(r) {
+				want = append(want, unicode.ToLower(r))
+			}
+		}
+		for i, p := range got {
+			if unicode.ToLower(text[p]) != want[i] {
+				t.Errorf("fuzzyMatch(%q, %q): position %d is %q, not %q", tt.pattern, tt.text, p, text[p], want[i])
+			}
+		}
+	}
+}
@@ -0,0 +1,247 @@
+package main
+
+import (
+	"bytes"
+	"embed"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"io/fs"
+	"math"
+	"net/http"
+	"strconv"
+	"time"
+)
+
+// ============================================================
+// WEB DASHBOARD
+// ============================================================
+
+//go:embed web
+var webFiles embed.FS
+
+type Server struct {
+	db *Database
+}
+
+type habitJSON struct {
+	ID            int    `json:"id"`
+	Name          string `json:"name"`
+	CurrentStreak int    `json:"currentStreak"`
+	TotalDone     int    `json:"totalDone"`
+	Level         int    `json:"level"`
+	XP            int    `json:"xp"`
+	Coins         int    `json:"coins"`
+	Strength      int    `json:"strength"`      // percent
+	StrengthTrend int    `json:"strengthTrend"` // points gained over the last week
+	DoneToday     bool   `json:"doneToday"`
+}
+
+type heatmapJSON struct {
+	Habit          habitJSON       `json:"habit"`
+	Weeks          int             `json:"weeks"`
+	Days           [][]heatmapCell `json:"days"` // 7 rows (Sun..Sat) of one cell per week
+	DaysShown      int             `json:"daysShown"`
+	CompletionRate float64         `json:"completionRate"`
+	BestStreak     int             `json:"bestStreak"`
+	Achievements   []string        `json:"achievements"`
+	Recent         []recentCheckIn `json:"recent"`
+}
+
+func runServe(args []string) error {
+	fset := flag.NewFlagSet("serve", flag.ContinueOnError)
+	addr := fset.String("addr", "127.0.0.1:8080", "address to listen on")
+	if err := fset.Parse(args); err != nil {
+		return err
+	}
+
+	cfg, err := loadConfig()
+	if err != nil {
+		return err
+	}
+
+	db, err := NewDatabase()
+	if err != nil {
+		return err
+	}
+	defer db.Close()
+
+	if err := db.RefreshStrength(); err != nil {
+		return err
+	}
+	if err := db.RecordAchievements(); err != nil {
+		return err
+	}
+
+	closeIntegrations := attachIntegrations(db, cfg)
+	defer closeIntegrations()
+
+	srv := &Server{db: db}
+	fmt.Printf("Serving dashboard on http://%s\n", *addr)
+	return http.ListenAndServe(*addr, srv.routes())
+}
+
+func (s *Server) routes() http.Handler {
+	static, err := fs.Sub(webFiles, "web")
+	if err != nil {
+		panic(err)
+	}
+
+	mux := http.NewServeMux()
+	mux.Handle("GET /", http.FileServerFS(static))
+	mux.HandleFunc("GET /api/habits", s.handleHabits)
+	mux.HandleFunc("POST /api/habits/{id}/toggle", s.handleToggle)
+	mux.HandleFunc("GET /api/habits/{id}/heatmap", s.handleHeatmap)
+	mux.HandleFunc("GET /calendar.ics", s.handleCalendar)
+
+	// Toggles change data, so refuse them from other sites' pages: a browser
+	// request whose Origin or Sec-Fetch-Site is not this server gets a 403.
+	return http.NewCrossOriginProtection().Handler(mux)
+}
+
+func (s *Server) handleHabits(w http.ResponseWriter, r *http.Request) {
+	habits, err := s.db.GetHabits()
+	if err != nil {
+		writeError(w, http.StatusInternalServerError, err)
+		return
+	}
+
+	done, err := s.db.GetDoneOn(time.Now().Format("2006-01-02"))
+	if err != nil {
+		writeError(w, http.StatusInternalServerError, err)
+		return
+	}
+
+	out := make([]habitJSON, 0, len(habits))
+	for _, h := range habits {
+		out = append(out, toHabitJSON(h, done[h.ID]))
+	}
+	writeJSON(w, out)
+}
+
+func (s *Server) handleToggle(w http.ResponseWriter, r *http.Request) {
+	id, err := strconv.Atoi(r.PathValue("id"))
+	if err != nil {
+		writeError(w, http.StatusBadRequest, fmt.Errorf("invalid habit id"))
+		return
+	}
+
+	if _, err := s.db.GetHabit(id); err != nil {
+		writeError(w, http.StatusNotFound, err)
+		return
+	}
+
+	isDone, err := s.db.ToggleHabit(id, time.Now().Format("2006-01-02"))
+	if err != nil {
+		writeError(w, http.StatusInternalServerError, err)
+		return
+	}
+
+	habit, err := s.db.GetHabit(id)
+	if err != nil {
+		writeError(w, http.StatusInternalServerError, err)
+		return
+	}
+	writeJSON(w, toHabitJSON(habit, isDone))
+}
+
+func (s *Server) handleHeatmap(w http.ResponseWriter, r *http.Request) {
+	id, err := strconv.Atoi(r.PathValue("id"))
+	if err != nil {
+		writeError(w, http.StatusBadRequest, fmt.Errorf("invalid habit id"))
+		return
+	}
+
+	weeks := 12
+	if v := r.URL.Query().Get("weeks"); v != "" {
+		weeks, err = strconv.Atoi(v)
+		if err != nil || weeks < minWeeks || weeks > maxWeeks {
+			writeError(w, http.StatusBadRequest, fmt.Errorf("weeks must be between %d and %d", minWeeks, maxWeeks))
+			return
+		}
+	}
+
+	habit, err := s.db.GetHabit(id)
+	if err != nil {
+		writeError(w, http.StatusNotFound, err)
+		return
+	}
+
+	logs, err := s.db.GetLogs(id, maxLogDays)
+	if err != nil {
+		writeError(w, http.StatusInternalServerError, err)
+		return
+	}
+
+	logsWithTime, err := s.db.GetLogsWithTime(id, maxLogDays)
+	if err != nil {
+		writeError(w, http.StatusInternalServerError, err)
+		return
+	}
+
+	achievements, err := s.db.GetAchievements(id)
+	if err != nil {
+		writeError(w, http.StatusInternalServerError, err)
+		return
+	}
+
+	// Same week-aligned layout as viewHeatmap
+	endDate := time.Now()
+	today := endDate.Format("2006-01-02")
+	startDate, totalDays, _ := heatmapWindow(weeks, endDate)
+	days := heatmapCells(logs, weeks, endDate)
+
+	daysShown, completionRate := completionStats(logs, startDate, endDate, totalDays)
+
+	writeJSON(w, heatmapJSON{
+		Habit:          toHabitJSON(habit, logs[today]),
+		Weeks:          weeks,
+		Days:           days,
+		DaysShown:      daysShown,
+		CompletionRate: completionRate,
+		BestStreak:     calculateBestStreak(logs),
+		Achievements:   achievementLabels(achievements),
+		Recent:         recentCheckIns(logsWithTime, endDate),
+	})
+}
+
+// handleCalendar serves the same feed as `habit export --format ics` so
+// calendar apps can subscribe to it.
+func (s *Server) handleCalendar(w http.ResponseWriter, r *http.Request) {
+	var buf bytes.Buffer
+	if err := writeICS(&buf, s.db, time.Now()); err != nil {
+		writeError(w, http.StatusInternalServerError, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "text/calendar; charset=utf-8")
+	w.Write(buf.Bytes())
+}
+
+func toHabitJSON(h Habit, doneToday bool) habitJSON {
+	return habitJSON{
+		ID:            h.ID,
+		Name:          h.Name,
+		CurrentStreak: h.CurrentStreak,
+		TotalDone:     h.TotalDone,
+		Level:         h.Level,
+		XP:            h.XP,
+		Coins:         h.Coins,
+		Strength:      int(math.Round(h.Strength)),
+		StrengthTrend: int(math.Round(h.StrengthTrend)),
+		DoneToday:     doneToday,
+	}
+}
+
+func writeJSON(w http.ResponseWriter, v any) {
+	w.Header().Set("Content-Type", "application/json")
+	if err := json.NewEncoder(w).Encode(v); err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+	}
+}
+
+func writeError(w http.ResponseWriter, status int, err error) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	json.NewEncoder(w).Encode(map[string]string{"error": err.Error()})
+}
@@ -0,0 +1,91 @@
+package main
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestToggleRejectsCrossOrigin(t *testing.T) {
+	db := newTestDatabase(t)
+	if err := db.AddHabit("Read"); err != nil {
+		t.Fatal(err)
+	}
+	habits, err := db.GetHabits()
+	if err != nil {
+		t.Fatal(err)
+	}
+	path := "/api/habits/" + strconv.Itoa(habits[0].ID) + "/toggle"
+	routes := (&Server{db: db}).routes()
+
+	tests := []struct {
+		name    string
+		headers map[string]string
+		want    int
+	}{
+		{name: "same origin", headers: map[string]string{"Origin": "http://127.0.0.1:8080", "Sec-Fetch-Site": "same-origin"}, want: http.StatusOK},
+		{name: "origin matches host", headers: map[string]string{"Origin": "http://127.0.0.1:8080"}, want: http.StatusOK},
+		{name: "not a browser", want: http.StatusOK},
+		{name: "other site", headers: map[string]string{"Origin": "https://evil.example", "Sec-Fetch-Site": "cross-site"}, want: http.StatusForbidden},
+		{name: "other origin", headers: map[string]string{"Origin": "https://evil.example"}, want: http.StatusForbidden},
+		{name: "other port", headers: map[string]string{"Origin": "http://127.0.0.1:9090"}, want: http.StatusForbidden},
+	}
+
+	for _, tt := range tests {
+		req := httptest.NewRequest(http.MethodPost, "http://127.0.0.1:8080"+path, nil)
+		for k, v := range tt.headers {
+			req.Header.Set(k, v)
+		}
+		rec := httptest.NewRecorder()
+		routes.ServeHTTP(rec, req)
+		if rec.Code != tt.want {
+			t.Errorf("%s: status %d, want %d", tt.name, rec.Code, tt.want)
+		}
+	}
+
+	// Only the three accepted toggles reached the database
+	done, err := db.GetDoneOn(time.Now().Format("2006-01-02"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !done[habits[0].ID] {
+		t.Error("habit is not done after three accepted toggles")
+	}
+}
+
+func TestHeatmapShowsStrengthAchievementsAndRecent(t *testing.T) {
+	db := newTestDatabase(t)
+	if err := db.AddHabit("Read"); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := db.ToggleHabit(1, time.Now().Format("2006-01-02")); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := db.db.Exec("INSERT INTO achievements (habit_id, type) VALUES (1, 'streak_3')"); err != nil {
+		t.Fatal(err)
+	}
+
+	rec := httptest.NewRecorder()
+	(&Server{db: db}).routes().ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/api/habits/1/heatmap", nil))
+	if rec.Code != http.StatusOK {
+		t.Fatalf("status %d: %s", rec.Code, rec.Body)
+	}
+
+	var data heatmapJSON
+	if err := json.NewDecoder(rec.Body).Decode(&data); err != nil {
+		t.Fatal(err)
+	}
+	if data.Habit.Strength != 5 || data.Habit.StrengthTrend != 5 {
+		t.Errorf("strength %d, trend %d; want 5 and 5 after one check-in", data.Habit.Strength, data.Habit.StrengthTrend)
+	}
+	if len(data.Achievements) != 1 || !strings.Contains(data.Achievements[0], "3 Day Streak!") {
+		t.Errorf("achievements = %q, want the 3 day streak", data.Achievements)
+	}
+	if len(data.Recent) != 1 || data.Recent[0].Ago != "Today" || data.Recent[0].Date != time.Now().Format("Mon, Jan 2") {
+		t.Errorf("recent = %+v, want today's check-in", data.Recent)
+	}
+}
@@ -0,0 +1,157 @@
+package main
+
+import (
+	"cmp"
+	"fmt"
+	"slices"
+	"strings"
+)
+
+// ============================================================
+// SORTING
+// ============================================================
+
+type sortMode int
+
+const (
+	sortManual sortMode = iota
+	sortName
+	sortStreak
+	sortLevel
+	sortPending
+	sortTime
+)
+
+var sortModeNames = []string{"manual", "name", "streak", "level", "pending", "time"}
+
+func (s sortMode) String() string {
+	return sortModeNames[s]
+}
+
+func parseSortMode(name string) sortMode {
+	if i := slices.Index(sortModeNames, name); i >= 0 {
+		return sortMode(i)
+	}
+	return sortManual
+}
+
+// GetCheckInTimes returns the median time of day, in minutes after
+// midnight, at which each habit is checked in. Backfilled check-ins are
+// ignored since their timestamp is not on the logged day.
+func (d *Database) GetCheckInTimes() (map[int]int, error) {
+	rows, err := d.db.Query(`
+		SELECT habit_id, substr(timestamp, 12, 5)
+		FROM logs
+		WHERE substr(timestamp, 1, 10) = date
+		ORDER BY habit_id
+	`)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get check-in times: %w", err)
+	}
+	defer rows.Close()
+
+	minutes := make(map[int][]int)
+	for rows.Next() {
+		var id int
+		var clock string
+		if err := rows.Scan(&id, &clock); err != nil {
+			return nil, fmt.Errorf("failed to scan log: %w", err)
+		}
+		if m, err := parseClock(clock); err == nil {
+			minutes[id] = append(minutes[id], m)
+		}
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating logs: %w", err)
+	}
+
+	medians := make(map[int]int, len(minutes))
+	for id, m := range minutes {
+		slices.Sort(m)
+		medians[id] = m[len(m)/2]
+	}
+	return medians, nil
+}
+
+// sortHabits orders m.habits by the active sort mode. Ties keep the manual
+// order GetHabits returns.
+func (m *Model) sortHabits() error {
+	var times map[int]int
+	if m.sortMode == sortTime {
+		var err error
+		if times, err = m.db.GetCheckInTimes(); err != nil {
+			return err
+		}
+	}
+
+	slices.SortStableFunc(m.habits, func(a, b Habit) int {
+		switch m.sortMode {
+		case sortName:
+			return strings.Compare(strings.ToLower(a.Name), strings.ToLower(b.Name))
+		case sortStreak:
+			return cmp.Compare(b.CurrentStreak, a.CurrentStreak)
+		case sortLevel:
+			return cmp.Or(cmp.Compare(b.Level, a.Level), cmp.Compare(b.XP, a.XP))
+		case sortPending:
+			return cmp.Compare(boolRank(m.doneToday[a.ID]), boolRank(m.doneToday[b.ID]))
+		case sortTime:
+			// Habits never checked in on time go last
+			ta, okA := times[a.ID]
+			tb, okB := times[b.ID]
+			return cmp.Or(cmp.Compare(boolRank(!okA), boolRank(!okB)), cmp.Compare(ta, tb))
+		}
+		return 0
+	})
+	return nil
+}
+
+func boolRank(b bool) int {
+	if b {
+		return 1
+	}
+	return 0
+}
+
+// cycleSort switches to the next sort mode and remembers it.
+func (m *Model) cycleSort() {
+	m.sortMode = (m.sortMode + 1) % sortMode(len(sortModeNames))
+	if err := m.db.SetSetting("sort", m.sortMode.String()); err != nil {
+		m.setError(err)
+		return
+	}
+	if err := m.refresh(); err != nil {
+		m.setError(err)
+		return
+	}
+	m.setMessage("Sorted by "+m.sortMode.String(), "info")
+}
+
+// moveSelected swaps the selected habit with its neighbour in the given
+// direction (-1 up, +1 down) within the same group.
+func (m *Model) moveSelected(dir int) {
+	habit, ok := m.selected()
+	if !ok {
+		return
+	}
+	if m.sortMode != sortManual {
+		m.setMessage("Press '"+m.keys.Sort.Help().Key+"' until the sort is manual to reorder habits", "info")
+		return
+	}
+
+	next := m.cursor + dir
+	if next < 0 || next >= len(m.rows) || m.rows[next].header {
+		return
+	}
+	other := m.habits[m.rows[next].habit]
+
+	if err := m.db.SwapHabits(habit.ID, other.ID); err != nil {
+		m.setError(err)
+		return
+	}
+	if err := m.refresh(); err != nil {
+		m.setError(err)
+		return
+	}
+	m.selectHabit(habit.ID)
+}
@@ -0,0 +1,170 @@
+package main
+
+import (
+	"encoding/json"
+	"flag"
+	"fmt"
+	"os"
+	"text/template"
+	"time"
+)
+
+// ============================================================
+// STATUS
+// ============================================================
+
+const defaultStatusFormat = "{{.Done}}/{{.Total}} habits done, 🔥{{.Streak}}"
+
+// exitCode is returned by commands that need a specific process exit status.
+type exitCode int
+
+func (c exitCode) Error() string {
+	return fmt.Sprintf("exit status %d", int(c))
+}
+
+// exitPending is the status command's exit code when habits remain today.
+const exitPending exitCode = 2
+
+type statusHabit struct {
+	ID     int    `json:"id"`
+	Name   string `json:"name"`
+	Done   bool   `json:"done"`
+	Streak int    `json:"streak"`
+}
+
+type statusSummary struct {
+	Date    string        `json:"date"`
+	Done    int           `json:"done"`
+	Total   int           `json:"total"`
+	Pending int           `json:"pending"`
+	Streak  int           `json:"streak"` // longest current streak
+	Habits  []statusHabit `json:"habits"`
+}
+
+// PendingNames lists the habits not yet done, for use in templates.
+func (s statusSummary) PendingNames() []string {
+	var names []string
+	for _, h := range s.Habits {
+		if !h.Done {
+			names = append(names, h.Name)
+		}
+	}
+	return names
+}
+
+// GetLastDone returns the date each habit was last done, for habits done
+// at least once.
+func (d *Database) GetLastDone() (map[int]string, error) {
+	rows, err := d.db.Query("SELECT habit_id, MAX(date) FROM logs GROUP BY habit_id")
+	if err != nil {
+		return nil, fmt.Errorf("failed to get last check-ins: %w", err)
+	}
+	defer rows.Close()
+
+	last := make(map[int]string)
+	for rows.Next() {
+		var id int
+		var date string
+		if err := rows.Scan(&id, &date); err != nil {
+			return nil, fmt.Errorf("failed to scan last check-in: %w", err)
+		}
+		last[id] = date
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating last check-ins: %w", err)
+	}
+
+	return last, nil
+}
+
+func buildStatus(db *Database, now time.Time) (statusSummary, error) {
+	summary := statusSummary{Date: now.Format("2006-01-02")}
+
+	habits, err := db.GetHabits()
+	if err != nil {
+		return summary, err
+	}
+
+	done, err := db.GetDoneOn(summary.Date)
+	if err != nil {
+		return summary, err
+	}
+
+	lastDone, err := db.GetLastDone()
+	if err != nil {
+		return summary, err
+	}
+	yesterday := now.AddDate(0, 0, -1).Format("2006-01-02")
+
+	summary.Habits = make([]statusHabit, 0, len(habits))
+	for _, h := range habits {
+		// The stored streak only changes on a toggle, so it outlives a
+		// missed day; it is current only if the habit was done since
+		// yesterday
+		streak := h.CurrentStreak
+		if last := lastDone[h.ID]; last != summary.Date && last != yesterday {
+			streak = 0
+		}
+
+		summary.Habits = append(summary.Habits, statusHabit{
+			ID:     h.ID,
+			Name:   h.Name,
+			Done:   done[h.ID],
+			Streak: streak,
+		})
+
+		summary.Total++
+		if done[h.ID] {
+			summary.Done++
+		}
+		summary.Streak = max(summary.Streak, streak)
+	}
+	summary.Pending = summary.Total - summary.Done
+
+	return summary, nil
+}
+
+// runStatus implements `habit status`. It exits with exitPending when any
+// habit is still pending today, so scripts can test the result directly.
+func runStatus(args []string) error {
+	fset := flag.NewFlagSet("status", flag.ContinueOnError)
+	format := fset.String("format", defaultStatusFormat, "Go template for the summary line")
+	asJSON := fset.Bool("json", false, "print the summary as JSON")
+	if err := fset.Parse(args); err != nil {
+		return err
+	}
+
+	tmpl, err := template.New("status").Parse(*format)
+	if err != nil {
+		return fmt.Errorf("invalid format: %w", err)
+	}
+
+	db, err := NewDatabase()
+	if err != nil {
+		return err
+	}
+	defer db.Close()
+
+	summary, err := buildStatus(db, time.Now())
+	if err != nil {
+		return err
+	}
+
+	if *asJSON {
+		enc := json.NewEncoder(os.Stdout)
+		if err := enc.Encode(summary); err != nil {
+			return err
+		}
+	} else {
+		if err := tmpl.Execute(os.Stdout, summary); err != nil {
+			return fmt.Errorf("failed to render format: %w", err)
+		}
+		fmt.Println()
+	}
+
+	if summary.Pending > 0 {
+		return exitPending
+	}
+	return nil
+}
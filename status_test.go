@@ -0,0 +1,93 @@
+package main
+
+import (
+	"bytes"
+	"os"
+	"testing"
+	"time"
+)
+
+func TestStatusStreakEndsAfterAMissedDay(t *testing.T) {
+	db := newTestDatabase(t)
+	now := time.Now()
+	day := func(offset int) string { return now.AddDate(0, 0, offset).Format("2006-01-02") }
+
+	// Stored streaks as the last toggle of each habit left them
+	habits := []struct {
+		name   string
+		streak int
+		last   string
+		want   int
+	}{
+		{name: "Read", streak: 4, last: day(0), want: 4},
+		{name: "Walk", streak: 9, last: day(-1), want: 9},
+		{name: "Run", streak: 30, last: day(-3), want: 0},
+		{name: "Swim", streak: 0, want: 0},
+	}
+	for i, h := range habits {
+		if err := db.AddHabit(h.name); err != nil {
+			t.Fatal(err)
+		}
+		if _, err := db.db.Exec("UPDATE habits SET current_streak = ? WHERE id = ?", h.streak, i+1); err != nil {
+			t.Fatal(err)
+		}
+		if h.last != "" {
+			if _, err := db.db.Exec("INSERT INTO logs (habit_id, date) VALUES (?, ?)", i+1, h.last); err != nil {
+				t.Fatal(err)
+			}
+		}
+	}
+
+	summary, err := buildStatus(db, now)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for i, h := range habits {
+		if got := summary.Habits[i].Streak; got != h.want {
+			t.Errorf("%s streak = %d, want %d", h.name, got, h.want)
+		}
+	}
+	if summary.Streak != 9 {
+		t.Errorf("longest streak = %d, want 9", summary.Streak)
+	}
+	if summary.Done != 1 || summary.Pending != 3 {
+		t.Errorf("done %d, pending %d; want 1 and 3", summary.Done, summary.Pending)
+	}
+}
+
+func TestStatusLeavesTheDatabaseUntouched(t *testing.T) {
+	db := newTestDatabase(t)
+	if err := db.AddHabit("Read"); err != nil {
+		t.Fatal(err)
+	}
+	var version int
+	if err := db.db.QueryRow("PRAGMA user_version").Scan(&version); err != nil {
+		t.Fatal(err)
+	}
+	if version != schemaVersion {
+		t.Fatalf("user_version = %d, want %d", version, schemaVersion)
+	}
+	db.Close()
+
+	before, err := os.ReadFile("habits.db")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	db, err = NewDatabase()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := buildStatus(db, time.Now()); err != nil {
+		t.Fatal(err)
+	}
+	db.Close()
+
+	after, err := os.ReadFile("habits.db")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !bytes.Equal(before, after) {
+		t.Error("opening an up-to-date database for status wrote to it")
+	}
+}
@@ -0,0 +1,144 @@
+package main
+
+import (
+	"fmt"
+	"math"
+	"time"
+
+	"github.com/charmbracelet/lipgloss"
+)
+
+// ============================================================
+// HABIT STRENGTH
+// ============================================================
+
+// strengthDecay is how much of the previous day's strength carries over to
+// the next, so a day's weight halves every 13 days.
+var strengthDecay = math.Pow(0.5, 1.0/13)
+
+// strengthTrendDays is how far back the trend arrow compares strength.
+const strengthTrendDays = 7
+
+// strengthHistory returns a habit's strength, from 0 to 100, at the end of
+// each of the last n days, oldest first. Strength is a decaying average of
+// every day since the first check-in: each done day pulls it toward 100 and
+// each missed day toward 0, recent days counting most, so one miss dents it
+// instead of resetting it like a streak. dates may be in any order.
+func strengthHistory(dates []string, n int, now time.Time) []float64 {
+	done := make(map[string]bool, len(dates))
+	first := ""
+	for _, d := range dates {
+		done[d] = true
+		if first == "" || d < first {
+			first = d
+		}
+	}
+
+	history := make([]float64, n)
+	// Dates are local days, so step through them in now's zone
+	start, err := time.ParseInLocation("2006-01-02", first, now.Location())
+	if err != nil {
+		return history
+	}
+
+	today := startOfDay(now)
+	from := today.AddDate(0, 0, -(n - 1))
+	strength := 0.0
+	for d := start; !d.After(today); d = d.AddDate(0, 0, 1) {
+		strength *= strengthDecay
+		if done[d.Format("2006-01-02")] {
+			strength += 1 - strengthDecay
+		}
+		if !d.Before(from) {
+			history[int(d.Sub(from).Hours()/24+0.5)] = strength * 100
+		}
+	}
+	return history
+}
+
+// habitStrength returns a habit's strength today and how many points it
+// changed over the last week.
+func habitStrength(dates []string, now time.Time) (strength, trend float64) {
+	history := strengthHistory(dates, strengthTrendDays+1, now)
+	return history[strengthTrendDays], history[strengthTrendDays] - history[0]
+}
+
+// liveStrength works out a habit's strength from its check-ins without
+// storing it, for commands that only read the database.
+func liveStrength(logs map[string]bool, now time.Time) (strength, trend float64) {
+	dates := make([]string, 0, len(logs))
+	for date := range logs {
+		dates = append(dates, date)
+	}
+	return habitStrength(dates, now)
+}
+
+// RefreshStrength brings every habit's strength up to date. Strength decays
+// on days without a check-in, so it goes stale while the tracker is closed;
+// the TUI, the dashboard server and the reminder daemon refresh it.
+func (d *Database) RefreshStrength() error {
+	logs, err := d.GetLogsSince("")
+	if err != nil {
+		return err
+	}
+
+	rows, err := d.db.Query("SELECT id FROM habits")
+	if err != nil {
+		return fmt.Errorf("failed to get habits: %w", err)
+	}
+	var ids []int
+	for rows.Next() {
+		var id int
+		if err := rows.Scan(&id); err != nil {
+			rows.Close()
+			return fmt.Errorf("failed to scan habit: %w", err)
+		}
+		ids = append(ids, id)
+	}
+	rows.Close()
+	if err := rows.Err(); err != nil {
+		return fmt.Errorf("error iterating habits: %w", err)
+	}
+
+	tx, err := d.db.Begin()
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	now := time.Now()
+	for _, id := range ids {
+		strength, trend := liveStrength(logs[id], now)
+		if _, err := tx.Exec("UPDATE habits SET strength = ?, strength_trend = ? WHERE id = ?", strength, trend, id); err != nil {
+			return fmt.Errorf("failed to update strength: %w", err)
+		}
+	}
+
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("failed to commit transaction: %w", err)
+	}
+	return nil
+}
+
+// strengthArrow shows which way strength moved over the last week; changes
+// under a point count as steady.
+func strengthArrow(trend float64) string {
+	switch {
+	case trend >= 1:
+		return lipgloss.NewStyle().Foreground(theme.Success).Render(glyph("↑", "+"))
+	case trend <= -1:
+		return lipgloss.NewStyle().Foreground(theme.Error).Render(glyph("↓", "-"))
+	}
+	return dimStyle.Render(glyph("→", "="))
+}
+
+// describeTrend is the weekly strength change in words.
+func describeTrend(trend float64) string {
+	switch {
+	case trend >= 1:
+		return fmt.Sprintf("up %.0f points this week", trend)
+	case trend <= -1:
+		return fmt.Sprintf("down %.0f points this week", -trend)
+	}
+	return "steady this week"
+}
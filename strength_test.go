@@ -0,0 +1,91 @@
+package main
+
+import (
+	"math"
+	"testing"
+	"time"
+)
+
+func TestHabitStrength(t *testing.T) {
+	// doneDays lists the days from offset from to offset to, inclusive
+	doneDays := func(now time.Time, from, to int) []string {
+		var dates []string
+		for i := from; i <= to; i++ {
+			dates = append(dates, now.AddDate(0, 0, i).Format("2006-01-02"))
+		}
+		return dates
+	}
+	// after is the strength k days into an unbroken run
+	after := func(k int) float64 { return (1 - math.Pow(strengthDecay, float64(k))) * 100 }
+
+	tests := []struct {
+		name         string
+		dates        func(now time.Time) []string
+		wantStrength float64
+		wantTrend    float64
+	}{
+		{
+			name:         "no check-ins",
+			dates:        func(now time.Time) []string { return nil },
+			wantStrength: 0,
+			wantTrend:    0,
+		},
+		{
+			name:         "thirty days in a row",
+			dates:        func(now time.Time) []string { return doneDays(now, -29, 0) },
+			wantStrength: after(30),
+			wantTrend:    after(30) - after(23),
+		},
+		{
+			name:         "halves after 13 days without one",
+			dates:        func(now time.Time) []string { return doneDays(now, -42, -13) },
+			wantStrength: after(30) / 2,
+			wantTrend:    after(30)/2 - after(30)*math.Pow(strengthDecay, 6),
+		},
+		{
+			name: "one miss dents rather than resets",
+			dates: func(now time.Time) []string {
+				return append(doneDays(now, -29, -2), doneDays(now, 0, 0)...)
+			},
+			wantStrength: after(28)*strengthDecay*strengthDecay + (1-strengthDecay)*100,
+			wantTrend:    after(28)*strengthDecay*strengthDecay + (1-strengthDecay)*100 - after(23),
+		},
+	}
+
+	zones := []*time.Location{
+		time.UTC,
+		time.FixedZone("UTC+2", 2*60*60),
+		time.FixedZone("UTC+9", 9*60*60),
+		time.FixedZone("UTC-5", -5*60*60),
+	}
+
+	local := time.Local
+	t.Cleanup(func() { time.Local = local })
+
+	for _, zone := range zones {
+		time.Local = zone
+		now := time.Date(2026, 10, 18, 23, 30, 0, 0, time.Local)
+
+		for _, tt := range tests {
+			strength, trend := habitStrength(tt.dates(now), now)
+			if math.Abs(strength-tt.wantStrength) > 0.01 || math.Abs(trend-tt.wantTrend) > 0.01 {
+				t.Errorf("%s in %s: strength %.2f, trend %+.2f; want %.2f, %+.2f",
+					tt.name, zone, strength, trend, tt.wantStrength, tt.wantTrend)
+			}
+		}
+	}
+}
+
+func TestStrengthHistoryIsOldestFirst(t *testing.T) {
+	now := time.Date(2026, 10, 18, 12, 0, 0, 0, time.Local)
+	dates := []string{now.AddDate(0, 0, -2).Format("2006-01-02")}
+
+	history := strengthHistory(dates, 4, now)
+	want := []float64{0, (1 - strengthDecay) * 100, (1 - strengthDecay) * strengthDecay * 100, (1 - strengthDecay) * strengthDecay * strengthDecay * 100}
+	for i := range want {
+		if math.Abs(history[i]-want[i]) > 1e-9 {
+			t.Errorf("history = %v, want %v", history, want)
+			break
+		}
+	}
+}
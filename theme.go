@@ -0,0 +1,299 @@
+package main
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+
+	"github.com/charmbracelet/lipgloss"
+)
+
+// ============================================================
+// THEMES
+// ============================================================
+
+const defaultThemeName = "default"
+
+// Theme holds every color the TUI uses. User themes are JSON files in the
+// themes directory next to config.json, named <theme>.json; fields they
+// leave out come from their "base" theme (default unless set).
+type Theme struct {
+	Accent    lipgloss.Color   `json:"accent"`    // titles, borders, selected row
+	Text      lipgloss.Color   `json:"text"`      // habit names
+	Dim       lipgloss.Color   `json:"dim"`       // hints and secondary text
+	Label     lipgloss.Color   `json:"label"`     // stat and weekday labels
+	Weekend   lipgloss.Color   `json:"weekend"`   // Sat/Sun labels
+	Selection lipgloss.Color   `json:"selection"` // selected row background, heatmap border
+	Success   lipgloss.Color   `json:"success"`
+	Error     lipgloss.Color   `json:"error"`
+	Warning   lipgloss.Color   `json:"warning"`   // streaks, levels, info messages
+	Highlight lipgloss.Color   `json:"highlight"` // coins, search matches
+	Heatmap   []lipgloss.Color `json:"heatmap"`   // no activity, then levels 1-4
+}
+
+var themes = map[string]Theme{
+	"default": {
+		Accent:    "#7D56F4",
+		Text:      "#FAFAFA",
+		Dim:       "#626262",
+		Label:     "#AAAAAA",
+		Weekend:   "#888888",
+		Selection: "#3C3C3C",
+		Success:   "#39D353",
+		Error:     "#FF5F87",
+		Warning:   "#FFA500",
+		Highlight: "#FFD700",
+		Heatmap:   []lipgloss.Color{"#161B22", "#0E4429", "#006D32", "#26A641", "#39D353"},
+	},
+	"light-terminal": {
+		Accent:    "#5A3FC0",
+		Text:      "#1F2328",
+		Dim:       "#8C959F",
+		Label:     "#57606A",
+		Weekend:   "#6E7781",
+		Selection: "#DDDEE3",
+		Success:   "#1A7F37",
+		Error:     "#CF222E",
+		Warning:   "#BC4C00",
+		Highlight: "#9A6700",
+		Heatmap:   []lipgloss.Color{"#D0D7DE", "#9BE9A8", "#40C463", "#30A14E", "#216E39"},
+	},
+	"high-contrast": {
+		Accent:    "#00FFFF",
+		Text:      "#FFFFFF",
+		Dim:       "#C0C0C0",
+		Label:     "#FFFFFF",
+		Weekend:   "#E0E0E0",
+		Selection: "#0000AF",
+		Success:   "#00FF00",
+		Error:     "#FF0000",
+		Warning:   "#FFFF00",
+		Highlight: "#FF00FF",
+		Heatmap:   []lipgloss.Color{"#3A3A3A", "#005F00", "#00AF00", "#00D700", "#00FF00"},
+	},
+	"solarized": {
+		Accent:    "#6C71C4",
+		Text:      "#93A1A1",
+		Dim:       "#586E75",
+		Label:     "#839496",
+		Weekend:   "#657B83",
+		Selection: "#073642",
+		Success:   "#859900",
+		Error:     "#DC322F",
+		Warning:   "#CB4B16",
+		Highlight: "#B58900",
+		Heatmap:   []lipgloss.Color{"#073642", "#3F5212", "#5F7308", "#859900", "#A8C023"},
+	},
+	// Blue sequential heatmap, distinguishable with red-green color blindness
+	"colorblind": {
+		Accent:    "#7D56F4",
+		Text:      "#FAFAFA",
+		Dim:       "#626262",
+		Label:     "#AAAAAA",
+		Weekend:   "#888888",
+		Selection: "#3C3C3C",
+		Success:   "#56B4E9",
+		Error:     "#D55E00",
+		Warning:   "#E69F00",
+		Highlight: "#F0E442",
+		Heatmap:   []lipgloss.Color{"#161B22", "#0C2C84", "#225EA8", "#1D91C0", "#7FCDBB"},
+	},
+}
+
+// theme is the active theme; applyTheme sets it.
+var theme Theme
+
+func init() {
+	applyTheme(themes[defaultThemeName])
+}
+
+// applyTheme rebuilds the shared styles from t.
+func applyTheme(t Theme) {
+	theme = t
+
+	titleStyle = lipgloss.NewStyle().Bold(true).Foreground(t.Accent).Padding(0, 1)
+	subtitleStyle = lipgloss.NewStyle().Foreground(t.Accent)
+	selectedStyle = lipgloss.NewStyle().Bold(true).Foreground(t.Accent).Background(t.Selection).Padding(0, 1)
+	normalStyle = lipgloss.NewStyle().Foreground(t.Text)
+	dimStyle = lipgloss.NewStyle().Foreground(t.Dim)
+	successStyle = lipgloss.NewStyle().Foreground(t.Success).Bold(true)
+	errorStyle = lipgloss.NewStyle().Foreground(t.Error).Bold(true)
+	streakStyle = lipgloss.NewStyle().Foreground(t.Warning).Bold(true)
+	warningStyle = lipgloss.NewStyle().Foreground(t.Warning)
+
+	colorNone = t.Heatmap[0]
+	colorLevel1 = t.Heatmap[1]
+	colorLevel2 = t.Heatmap[2]
+	colorLevel3 = t.Heatmap[3]
+	colorLevel4 = t.Heatmap[4]
+
+	boxStyle = lipgloss.NewStyle().
+		Border(roundedBorder()).
+		BorderForeground(t.Accent).
+		Padding(1, 2)
+
+	heatmapBoxStyle = lipgloss.NewStyle().
+		Border(normalBorder()).
+		BorderForeground(t.Selection).
+		Padding(1, 2)
+}
+
+// noColor reports whether NO_COLOR is set. lipgloss then renders without
+// any colors or attributes, so views must not rely on them alone.
+func noColor() bool {
+	return os.Getenv("NO_COLOR") != ""
+}
+
+func themesDir() (string, error) {
+	dir, err := configDir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(dir, "themes"), nil
+}
+
+// loadTheme returns the named theme, preferring a user theme file over a
+// built-in theme of the same name.
+func loadTheme(name string) (Theme, error) {
+	return loadThemeDepth(name, 0)
+}
+
+func loadThemeDepth(name string, depth int) (Theme, error) {
+	if depth > 5 {
+		return Theme{}, fmt.Errorf("theme %q: too many levels of base themes", name)
+	}
+
+	dir, err := themesDir()
+	if err != nil {
+		return Theme{}, err
+	}
+
+	data, err := os.ReadFile(filepath.Join(dir, name+".json"))
+	if errors.Is(err, fs.ErrNotExist) {
+		if t, ok := themes[name]; ok {
+			return t, nil
+		}
+		names, _ := themeNames()
+		return Theme{}, fmt.Errorf("unknown theme %q (available: %s)", name, strings.Join(names, ", "))
+	}
+	if err != nil {
+		return Theme{}, fmt.Errorf("failed to read theme %s: %w", name, err)
+	}
+
+	var header struct {
+		Base string `json:"base"`
+	}
+	if err := json.Unmarshal(data, &header); err != nil {
+		return Theme{}, fmt.Errorf("invalid theme %s: %w", name, err)
+	}
+	// A file named after a built-in theme customizes that theme unless it
+	// names another base
+	base, builtin := themes[name]
+	if header.Base == "" {
+		header.Base = defaultThemeName
+		if builtin {
+			header.Base = name
+		}
+	}
+	if header.Base != name || !builtin {
+		if base, err = loadThemeDepth(header.Base, depth+1); err != nil {
+			return Theme{}, fmt.Errorf("theme %s: %w", name, err)
+		}
+	}
+
+	// Fields present in the file override the base theme
+	t := base
+	t.Heatmap = slices.Clone(base.Heatmap)
+	if err := json.Unmarshal(data, &t); err != nil {
+		return Theme{}, fmt.Errorf("invalid theme %s: %w", name, err)
+	}
+	if err := t.validate(); err != nil {
+		return Theme{}, fmt.Errorf("theme %s: %w", name, err)
+	}
+
+	return t, nil
+}
+
+func (t Theme) validate() error {
+	colors := map[string]lipgloss.Color{
+		"accent": t.Accent, "text": t.Text, "dim": t.Dim, "label": t.Label,
+		"weekend": t.Weekend, "selection": t.Selection, "success": t.Success,
+		"error": t.Error, "warning": t.Warning, "highlight": t.Highlight,
+	}
+	for field, c := range colors {
+		if !hexColorPattern.MatchString(string(c)) {
+			return fmt.Errorf("%s: invalid color %q (expected #RRGGBB)", field, c)
+		}
+	}
+
+	if len(t.Heatmap) != 5 {
+		return fmt.Errorf("heatmap: expected 5 colors (no activity, then levels 1-4), got %d", len(t.Heatmap))
+	}
+	for _, c := range t.Heatmap {
+		if !hexColorPattern.MatchString(string(c)) {
+			return fmt.Errorf("heatmap: invalid color %q (expected #RRGGBB)", c)
+		}
+	}
+
+	return nil
+}
+
+// themeNames lists built-in and user themes, sorted.
+func themeNames() ([]string, error) {
+	var names []string
+	for name := range themes {
+		names = append(names, name)
+	}
+
+	dir, err := themesDir()
+	if err != nil {
+		return nil, err
+	}
+	files, err := filepath.Glob(filepath.Join(dir, "*.json"))
+	if err != nil {
+		return nil, err
+	}
+	for _, f := range files {
+		name := strings.TrimSuffix(filepath.Base(f), ".json")
+		if !slices.Contains(names, name) {
+			names = append(names, name)
+		}
+	}
+
+	slices.Sort(names)
+	return names, nil
+}
+
+// runThemes implements `habit themes`, printing each theme with a preview
+// of its accent and heatmap colors.
+func runThemes(args []string) error {
+	if len(args) > 0 {
+		return fmt.Errorf("usage: habit themes")
+	}
+
+	names, err := themeNames()
+	if err != nil {
+		return err
+	}
+
+	for _, name := range names {
+		t, err := loadTheme(name)
+		if err != nil {
+			fmt.Printf("%-16s %v\n", name, err)
+			continue
+		}
+
+		var swatch strings.Builder
+		swatch.WriteString(lipgloss.NewStyle().Foreground(t.Accent).Render("■ "))
+		for _, c := range t.Heatmap {
+			swatch.WriteString(lipgloss.NewStyle().Foreground(c).Render("█"))
+		}
+		fmt.Printf("%-16s %s\n", name, swatch.String())
+	}
+	return nil
+}
@@ -0,0 +1,171 @@
+package main
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+// withThemes points the config directory at a temporary one holding the
+// given theme files.
+func withThemes(t *testing.T, files map[string]string) {
+	t.Helper()
+	config := t.TempDir()
+	t.Setenv("XDG_CONFIG_HOME", config)
+
+	dir := filepath.Join(config, "habit-tracker", "themes")
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		t.Fatal(err)
+	}
+	for name, body := range files {
+		if err := os.WriteFile(filepath.Join(dir, name+".json"), []byte(body), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+}
+
+func TestLoadUserTheme(t *testing.T) {
+	withThemes(t, map[string]string{
+		"ocean":     `{"accent": "#0077BE"}`,
+		"sunset":    `{"base": "solarized", "accent": "#D33682", "heatmap": ["#000000", "#111111", "#222222", "#333333", "#444444"]}`,
+		"dusk":      `{"base": "sunset", "text": "#EEEEEE"}`,
+		"solarized": `{"error": "#FF0000"}`,
+		"plain":     `{"base": "default", "accent": "#ABCDEF"}`,
+	})
+
+	tests := []struct {
+		name  string
+		check func(Theme) bool
+	}{
+		// Fields left out come from the default theme
+		{"ocean", func(th Theme) bool {
+			return th.Accent == "#0077BE" && th.Text == themes["default"].Text && len(th.Heatmap) == 5
+		}},
+		// ...or from the named base
+		{"sunset", func(th Theme) bool {
+			return th.Accent == "#D33682" && th.Text == themes["solarized"].Text && th.Heatmap[4] == "#444444"
+		}},
+		// Bases chain
+		{"dusk", func(th Theme) bool {
+			return th.Text == "#EEEEEE" && th.Accent == "#D33682" && th.Selection == themes["solarized"].Selection
+		}},
+		// A file named after a built-in theme customizes it
+		{"solarized", func(th Theme) bool {
+			return th.Error == "#FF0000" && th.Accent == themes["solarized"].Accent
+		}},
+		{"plain", func(th Theme) bool { return th.Accent == "#ABCDEF" && th.Text == themes["default"].Text }},
+		{"high-contrast", func(th Theme) bool { return th.Accent == themes["high-contrast"].Accent }},
+	}
+
+	for _, tt := range tests {
+		th, err := loadTheme(tt.name)
+		if err != nil {
+			t.Errorf("loadTheme(%q): %v", tt.name, err)
+			continue
+		}
+		if !tt.check(th) {
+			t.Errorf("loadTheme(%q) = %+v", tt.name, th)
+		}
+	}
+
+	// Loading a user theme must not change the built-in it is based on
+	if themes["solarized"].Heatmap[4] == "#444444" || themes["solarized"].Error == "#FF0000" {
+		t.Error("a user theme modified a built-in theme")
+	}
+}
+
+func TestLoadUserThemeErrors(t *testing.T) {
+	withThemes(t, map[string]string{
+		"short-hex":     `{"accent": "#12345"}`,
+		"named-color":   `{"text": "red"}`,
+		"not-hex":       `{"dim": "#GGGGGG"}`,
+		"four-levels":   `{"heatmap": ["#000000", "#111111", "#222222", "#333333"]}`,
+		"bad-level":     `{"heatmap": ["#000000", "#111111", "#222222", "#333333", "green"]}`,
+		"broken":        `{"accent": `,
+		"missing-base":  `{"base": "nowhere"}`,
+		"bad-base":      `{"base": "short-hex"}`,
+		"loop-a":        `{"base": "loop-b"}`,
+		"loop-b":        `{"base": "loop-a"}`,
+		"self":          `{"base": "self"}`,
+		"chain-1":       `{"base": "chain-2"}`,
+		"chain-2":       `{"base": "chain-3"}`,
+		"chain-3":       `{"base": "chain-4"}`,
+		"chain-4":       `{"base": "chain-5"}`,
+		"chain-5":       `{"base": "chain-6"}`,
+		"chain-6":       `{"base": "chain-7"}`,
+		"chain-7":       `{}`,
+		"chain-ok-1":    `{"base": "chain-ok-2"}`,
+		"chain-ok-2":    `{"base": "chain-ok-3"}`,
+		"chain-ok-3":    `{"base": "solarized"}`,
+		"string-accent": `{"accent": 7}`,
+	})
+
+	tests := []struct {
+		name    string
+		wantErr string
+	}{
+		{"short-hex", `accent: invalid color "#12345"`},
+		{"named-color", `text: invalid color "red"`},
+		{"not-hex", `dim: invalid color "#GGGGGG"`},
+		{"four-levels", "expected 5 colors"},
+		{"bad-level", `heatmap: invalid color "green"`},
+		{"broken", "invalid theme broken"},
+		{"string-accent", "invalid theme string-accent"},
+		{"missing-base", `unknown theme "nowhere"`},
+		{"bad-base", `theme short-hex: accent: invalid color`},
+		{"loop-a", "too many levels"},
+		{"self", "too many levels"},
+		{"chain-1", "too many levels"},
+		{"chain-ok-1", ""},
+		{"nowhere", "unknown theme"},
+	}
+
+	for _, tt := range tests {
+		_, err := loadTheme(tt.name)
+		if tt.wantErr == "" {
+			if err != nil {
+				t.Errorf("loadTheme(%q): %v", tt.name, err)
+			}
+			continue
+		}
+		if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+			t.Errorf("loadTheme(%q) error = %v, want one containing %q", tt.name, err, tt.wantErr)
+		}
+	}
+
+	// Unknown themes list what is available, user themes included
+	_, err := loadTheme("nowhere")
+	if err == nil || !strings.Contains(err.Error(), "solarized") || !strings.Contains(err.Error(), "loop-a") {
+		t.Errorf("unknown theme error = %v, want the available themes listed", err)
+	}
+}
+
+func TestBuiltinThemesAreValid(t *testing.T) {
+	for name, th := range themes {
+		if err := th.validate(); err != nil {
+			t.Errorf("%s: %v", name, err)
+		}
+	}
+}
+
+func TestNoColorMarksSelection(t *testing.T) {
+	m := newTestModel(t)
+	selected := glyph("› ", "> ")
+
+	t.Setenv("NO_COLOR", "")
+	if header := m.renderCategoryHeader(0, true); strings.Contains(header, selected) {
+		t.Errorf("header = %q, want no marker while colors show the selection", header)
+	}
+
+	t.Setenv("NO_COLOR", "1")
+	if !noColor() {
+		t.Fatal("noColor() = false with NO_COLOR set")
+	}
+	if header := m.renderCategoryHeader(0, true); !strings.Contains(header, selected) {
+		t.Errorf("header = %q, want the %q marker without colors", header, selected)
+	}
+	if header := m.renderCategoryHeader(0, false); strings.Contains(header, selected) {
+		t.Errorf("unselected header = %q, want no marker", header)
+	}
+}
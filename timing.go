@@ -0,0 +1,267 @@
+package main
+
+import (
+	"fmt"
+	"math"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/charmbracelet/bubbles/key"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+)
+
+// ============================================================
+// TIME OF DAY
+// ============================================================
+
+// timingWindow is the hour either side of the typical time within which a
+// check-in counts as on time for the consistency score.
+const timingWindow = 60
+
+type timingStats struct {
+	Samples    int // check-ins made on the day they are for
+	Backfilled int // check-ins skipped because they were made on a later day
+	Median     int // minutes after midnight
+	Early      int // first quartile, minutes after midnight
+	Late       int // third quartile, minutes after midnight
+	OnTime     float64
+	Hours      [24]int
+}
+
+type weekdayStats struct {
+	Due  int
+	Done int
+}
+
+// checkInMinutes returns the time of day, in minutes after midnight, of each
+// check-in made on the day it is for, and how many were backfilled.
+func checkInMinutes(logs map[string]LogEntry) ([]int, int) {
+	var minutes []int
+	backfilled := 0
+	for date, entry := range logs {
+		at, err := time.Parse("2006-01-02 15:04:05", entry.Timestamp)
+		if err != nil || at.Format("2006-01-02") != date {
+			backfilled++
+			continue
+		}
+		minutes = append(minutes, at.Hour()*60+at.Minute())
+	}
+	return minutes, backfilled
+}
+
+// computeTiming summarizes check-in times. The clock is read as starting
+// after the longest quiet stretch, so a habit done around midnight gets a
+// median near midnight rather than at noon.
+func computeTiming(minutes []int, backfilled int) timingStats {
+	stats := timingStats{Samples: len(minutes), Backfilled: backfilled}
+	if len(minutes) == 0 {
+		return stats
+	}
+
+	sorted := slices.Clone(minutes)
+	slices.Sort(sorted)
+	origin, gap := sorted[0], 0
+	for i, m := range sorted {
+		next := sorted[(i+1)%len(sorted)]
+		if g := (next - m + 1440) % 1440; g > gap {
+			origin, gap = next, g
+		}
+	}
+
+	shifted := make([]int, len(sorted))
+	for i, m := range sorted {
+		shifted[i] = (m - origin + 1440) % 1440
+		stats.Hours[m/60]++
+	}
+	slices.Sort(shifted)
+
+	unshift := func(m int) int { return (m + origin) % 1440 }
+	stats.Median = unshift(shifted[len(shifted)/2])
+	stats.Early = unshift(shifted[len(shifted)/4])
+	stats.Late = unshift(shifted[len(shifted)*3/4])
+
+	onTime := 0
+	for _, m := range sorted {
+		d := (m - stats.Median + 1440) % 1440
+		if min(d, 1440-d) <= timingWindow {
+			onTime++
+		}
+	}
+	stats.OnTime = float64(onTime) * 100 / float64(len(sorted))
+
+	return stats
+}
+
+// weekdayBreakdown counts, per weekday (Sunday first), the days from start
+// to now a habit was due and done. Today only counts once it is done.
+func weekdayBreakdown(logs map[string]LogEntry, start, now time.Time) [7]weekdayStats {
+	var days [7]weekdayStats
+	today := startOfDay(now)
+	for d := startOfDay(start); !d.After(today); d = d.AddDate(0, 0, 1) {
+		_, done := logs[d.Format("2006-01-02")]
+		if d.Equal(today) && !done {
+			continue
+		}
+		days[d.Weekday()].Due++
+		if done {
+			days[d.Weekday()].Done++
+		}
+	}
+	return days
+}
+
+// consistencyLabel puts the share of on-time check-ins into words.
+func consistencyLabel(onTime float64) string {
+	switch {
+	case onTime >= 80:
+		return "very consistent"
+	case onTime >= 50:
+		return "fairly consistent"
+	}
+	return "varies a lot"
+}
+
+func formatMinutes(m int) string {
+	return time.Date(2000, 1, 1, m/60, m%60, 0, 0, time.Local).Format("3:04 PM")
+}
+
+// openTiming shows when the selected habit tends to be done.
+func (m *Model) openTiming() {
+	habit, ok := m.selected()
+	if !ok {
+		m.setMessage("No habit selected to view", "info")
+		return
+	}
+
+	logs, err := m.db.GetLogsWithTime(habit.ID, maxLogDays)
+	if err != nil {
+		m.setError(err)
+		return
+	}
+	m.timingLogs = logs
+	m.mode = modeTiming
+}
+
+func (m *Model) updateTiming(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	switch {
+	case key.Matches(msg, m.keys.Back, m.keys.Timing):
+		m.mode = modeList
+
+	case key.Matches(msg, m.keys.Help):
+		m.showHelp()
+	}
+	return m, nil
+}
+
+// viewTiming shows the typical check-in time of the selected habit, a
+// histogram of check-ins by hour and how often each weekday is missed, all
+// over the last year.
+func (m *Model) viewTiming() string {
+	habit, ok := m.selected()
+	if !ok {
+		return ""
+	}
+
+	var s strings.Builder
+
+	s.WriteString(titleStyle.Render(glyph("⏰ ", "")+habit.Name) + "\n\n")
+
+	statRow := func(label, value string, color lipgloss.Color) string {
+		return lipgloss.NewStyle().Foreground(theme.Label).Width(20).Render(label) +
+			lipgloss.NewStyle().Foreground(color).Bold(true).Render(value)
+	}
+
+	minutes, backfilled := checkInMinutes(m.timingLogs)
+	stats := computeTiming(minutes, backfilled)
+
+	s.WriteString(subtitleStyle.Render("Time of day") + "\n\n")
+	if stats.Samples == 0 {
+		s.WriteString(dimStyle.Render("No check-ins made on the day yet; backfilled ones have no time of day.") + "\n\n")
+	} else {
+		dash := glyph("–", "-")
+		s.WriteString(statRow("Typical time:", formatMinutes(stats.Median), theme.Accent) + "\n")
+		s.WriteString(statRow("Usually between:", formatMinutes(stats.Early)+" "+dash+" "+formatMinutes(stats.Late), theme.Highlight) + "\n")
+		s.WriteString(statRow("Consistency:", fmt.Sprintf("%.0f%% within an hour (%s)", stats.OnTime, consistencyLabel(stats.OnTime)), theme.Success) + "\n")
+		based := plural(stats.Samples, "check-in")
+		if stats.Backfilled > 0 {
+			based += fmt.Sprintf(", %d backfilled skipped", stats.Backfilled)
+		}
+		s.WriteString(statRow("Based on:", based, theme.Dim) + "\n\n")
+
+		// Hour histogram, narrower bars when the window is tight
+		barWidth := 2
+		if width := m.contentWidth(); width > 0 && width < chartAxisWidth+24*3 {
+			barWidth = 1
+		}
+		shares := make([]float64, 24)
+		top := 0.0
+		for h, n := range stats.Hours {
+			shares[h] = float64(n) * 100 / float64(stats.Samples)
+			top = math.Max(top, shares[h])
+		}
+		top = math.Ceil(top/10) * 10
+
+		s.WriteString(subtitleStyle.Render("Check-ins by hour") + "\n\n")
+		lines := plotChart(shares, top, true, barWidth, func(v float64) string { return fmt.Sprintf("%.0f%%", v) })
+		s.WriteString(strings.Join(lines, "\n") + "\n")
+
+		axisStyle := lipgloss.NewStyle().Foreground(theme.Label)
+		s.WriteString(axisStyle.Render(strings.Repeat(" ", chartAxisWidth-2)+glyph("└", "+")+strings.Repeat(glyph("─", "-"), 24*(barWidth+1)+1)) + "\n")
+		var hours strings.Builder
+		for h := 0; h < 24; h += 3 {
+			hours.WriteString(fmt.Sprintf("%-*d", 3*(barWidth+1), h))
+		}
+		s.WriteString(axisStyle.Render(strings.Repeat(" ", chartAxisWidth)+hours.String()) + "\n\n")
+	}
+
+	// Weekdays, Monday first, over the same year as the check-ins
+	now := time.Now()
+	start := now.AddDate(0, 0, -maxLogDays)
+	if created, err := time.ParseInLocation("2006-01-02", habit.CreatedAt[:min(len(habit.CreatedAt), 10)], time.Local); err == nil && created.After(start) {
+		start = created
+	}
+	for date := range m.timingLogs {
+		if d, err := time.ParseInLocation("2006-01-02", date, time.Local); err == nil && d.Before(start) {
+			start = d
+		}
+	}
+	days := weekdayBreakdown(m.timingLogs, start, now)
+
+	worst, worstMissed := -1, 0
+	for wd, d := range days {
+		if missed := d.Due - d.Done; missed > worstMissed {
+			worst, worstMissed = wd, missed
+		}
+	}
+
+	s.WriteString(subtitleStyle.Render("By weekday") + "\n\n")
+	for i := range 7 {
+		wd := (i + 1) % 7
+		d := days[wd]
+		name := time.Weekday(wd).String()[:3]
+		rate := 0.0
+		if d.Due > 0 {
+			rate = float64(d.Done) * 100 / float64(d.Due)
+		}
+		nameStyle := lipgloss.NewStyle().Foreground(theme.Label)
+		if wd == worst {
+			nameStyle = lipgloss.NewStyle().Foreground(theme.Error).Bold(true)
+		}
+		s.WriteString(nameStyle.Width(5).Render(name) +
+			m.getProgressBar(d.Done, max(d.Due, 1), 20) +
+			normalStyle.Render(fmt.Sprintf("  %3.0f%%", rate)) +
+			dimStyle.Render(fmt.Sprintf("  %d of %d done", d.Done, d.Due)) + "\n")
+	}
+	s.WriteString("\n")
+
+	if worst >= 0 {
+		s.WriteString(warningStyle.Render(fmt.Sprintf("Missed most on %ss: %d of %d",
+			time.Weekday(worst), worstMissed, days[worst].Due)) + "\n\n")
+	}
+
+	s.WriteString(m.renderHelp())
+
+	return s.String()
+}
@@ -0,0 +1,265 @@
+package main
+
+import (
+	"bytes"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"flag"
+	"fmt"
+	"net/http"
+	"slices"
+	"sync"
+	"time"
+)
+
+// ============================================================
+// WEBHOOKS
+// ============================================================
+
+const (
+	defaultWebhookAttempts = 5
+	webhookBaseBackoff     = time.Second
+	webhookTimeout         = 10 * time.Second
+)
+
+// errWebhookAbandoned is logged for a retry cut short by shutdown.
+var errWebhookAbandoned = errors.New("abandoned: shutting down before this attempt")
+
+type WebhookDispatcher struct {
+	db      *Database
+	hooks   []WebhookConfig
+	client  *http.Client
+	backoff time.Duration // wait before the first retry, doubling after each
+	wg      sync.WaitGroup
+	done    chan struct{}
+	once    sync.Once
+}
+
+type WebhookDelivery struct {
+	URL         string
+	Event       string
+	Attempt     int
+	StatusCode  int
+	Error       string
+	DeliveredAt string
+}
+
+func NewWebhookDispatcher(db *Database, hooks []WebhookConfig) *WebhookDispatcher {
+	return &WebhookDispatcher{
+		db:      db,
+		hooks:   hooks,
+		client:  &http.Client{Timeout: webhookTimeout},
+		backoff: webhookBaseBackoff,
+		done:    make(chan struct{}),
+	}
+}
+
+// Dispatch queues e for every webhook subscribed to its type. Deliveries run
+// in the background so a slow endpoint never blocks a toggle.
+func (w *WebhookDispatcher) Dispatch(e Event) {
+	payload, err := json.Marshal(e)
+	if err != nil {
+		return
+	}
+
+	for _, hook := range w.hooks {
+		if len(hook.Events) > 0 && !slices.Contains(hook.Events, e.Type) && e.Type != EventPing {
+			continue
+		}
+
+		w.wg.Add(1)
+		go func(hook WebhookConfig) {
+			defer w.wg.Done()
+			w.deliver(hook, e.Type, payload)
+		}(hook)
+	}
+}
+
+// Close abandons pending retries, logging each as abandoned, and waits for
+// in-flight requests to finish.
+func (w *WebhookDispatcher) Close() {
+	w.once.Do(func() { close(w.done) })
+	w.wg.Wait()
+}
+
+func (w *WebhookDispatcher) deliver(hook WebhookConfig, eventType string, payload []byte) {
+	attempts := hook.MaxAttempts
+	if attempts <= 0 {
+		attempts = defaultWebhookAttempts
+	}
+
+	backoff := w.backoff
+	for attempt := 1; attempt <= attempts; attempt++ {
+		status, err := w.post(hook, eventType, payload)
+		w.logDelivery(hook.URL, eventType, payload, attempt, status, err)
+
+		if err == nil {
+			return
+		}
+
+		if attempt == attempts {
+			return
+		}
+
+		select {
+		case <-time.After(backoff):
+			backoff *= 2
+		case <-w.done:
+			w.logDelivery(hook.URL, eventType, payload, attempt+1, 0, errWebhookAbandoned)
+			return
+		}
+	}
+}
+
+func (w *WebhookDispatcher) post(hook WebhookConfig, eventType string, payload []byte) (int, error) {
+	req, err := http.NewRequest(http.MethodPost, hook.URL, bytes.NewReader(payload))
+	if err != nil {
+		return 0, err
+	}
+
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("User-Agent", "habit-tracker")
+	req.Header.Set("X-Habit-Event", eventType)
+	if hook.Secret != "" {
+		req.Header.Set("X-Habit-Signature", "sha256="+signPayload(hook.Secret, payload))
+	}
+
+	resp, err := w.client.Do(req)
+	if err != nil {
+		return 0, err
+	}
+	resp.Body.Close()
+
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return resp.StatusCode, fmt.Errorf("unexpected status %s", resp.Status)
+	}
+
+	return resp.StatusCode, nil
+}
+
+func signPayload(secret string, payload []byte) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write(payload)
+	return hex.EncodeToString(mac.Sum(nil))
+}
+
+func (w *WebhookDispatcher) logDelivery(url, eventType string, payload []byte, attempt, status int, deliveryErr error) {
+	var errText any
+	if deliveryErr != nil {
+		errText = deliveryErr.Error()
+	}
+
+	var statusCode any
+	if status != 0 {
+		statusCode = status
+	}
+
+	// Logging is best effort; a failed insert must not affect delivery.
+	w.db.db.Exec(`
+		INSERT INTO webhook_deliveries (url, event, payload, attempt, status_code, error, delivered_at)
+		VALUES (?, ?, ?, ?, ?, ?, ?)
+	`, url, eventType, string(payload), attempt, statusCode, errText, time.Now().Format("2006-01-02 15:04:05"))
+}
+
+func (d *Database) GetWebhookDeliveries(limit int) ([]WebhookDelivery, error) {
+	rows, err := d.db.Query(`
+		SELECT url, event, attempt, COALESCE(status_code, 0), COALESCE(error, ''), delivered_at
+		FROM webhook_deliveries
+		ORDER BY id DESC
+		LIMIT ?
+	`, limit)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get webhook deliveries: %w", err)
+	}
+	defer rows.Close()
+
+	var deliveries []WebhookDelivery
+	for rows.Next() {
+		var dl WebhookDelivery
+		if err := rows.Scan(&dl.URL, &dl.Event, &dl.Attempt, &dl.StatusCode, &dl.Error, &dl.DeliveredAt); err != nil {
+			return nil, fmt.Errorf("failed to scan webhook delivery: %w", err)
+		}
+		deliveries = append(deliveries, dl)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating webhook deliveries: %w", err)
+	}
+
+	return deliveries, nil
+}
+
+// runWebhooks implements `habit webhooks test|log`.
+func runWebhooks(args []string) error {
+	if len(args) == 0 {
+		return fmt.Errorf("usage: habit webhooks test|log")
+	}
+
+	cfg, err := loadConfig()
+	if err != nil {
+		return err
+	}
+
+	db, err := NewDatabase()
+	if err != nil {
+		return err
+	}
+	defer db.Close()
+
+	switch args[0] {
+	case "test":
+		if len(cfg.Webhooks) == 0 {
+			return fmt.Errorf("no webhooks configured")
+		}
+		// One attempt each, so the results shown are the real responses
+		hooks := slices.Clone(cfg.Webhooks)
+		for i := range hooks {
+			hooks[i].MaxAttempts = 1
+		}
+		dispatcher := NewWebhookDispatcher(db, hooks)
+		dispatcher.Dispatch(Event{Type: EventPing, Time: time.Now()})
+		dispatcher.Close()
+
+		deliveries, err := db.GetWebhookDeliveries(len(cfg.Webhooks))
+		if err != nil {
+			return err
+		}
+		printDeliveries(deliveries)
+		return nil
+
+	case "log":
+		fset := flag.NewFlagSet("webhooks log", flag.ContinueOnError)
+		limit := fset.Int("n", 20, "number of deliveries to show")
+		if err := fset.Parse(args[1:]); err != nil {
+			return err
+		}
+
+		deliveries, err := db.GetWebhookDeliveries(*limit)
+		if err != nil {
+			return err
+		}
+		printDeliveries(deliveries)
+		return nil
+
+	default:
+		return fmt.Errorf("unknown webhooks command %q", args[0])
+	}
+}
+
+func printDeliveries(deliveries []WebhookDelivery) {
+	if len(deliveries) == 0 {
+		fmt.Println("No deliveries yet")
+		return
+	}
+
+	for _, dl := range deliveries {
+		result := fmt.Sprintf("%d", dl.StatusCode)
+		if dl.Error != "" {
+			result = dl.Error
+		}
+		fmt.Printf("%s  %-22s  attempt %d  %s  %s\n", dl.DeliveredAt, dl.Event, dl.Attempt, dl.URL, result)
+	}
+}
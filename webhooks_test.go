@@ -0,0 +1,214 @@
+package main
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"slices"
+	"sync"
+	"testing"
+	"time"
+)
+
+// newTestDatabase opens an empty database in a temporary directory.
+func newTestDatabase(t *testing.T) *Database {
+	t.Helper()
+	t.Chdir(t.TempDir())
+
+	db, err := NewDatabase()
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { db.Close() })
+	return db
+}
+
+// webhookStub answers with the given statuses in turn, repeating the last,
+// and records each request.
+type webhookStub struct {
+	*httptest.Server
+	mu       sync.Mutex
+	statuses []int
+	times    []time.Time
+	bodies   [][]byte
+	headers  []http.Header
+}
+
+func newWebhookStub(t *testing.T, statuses ...int) *webhookStub {
+	s := &webhookStub{statuses: statuses}
+	s.Server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+
+		s.mu.Lock()
+		n := len(s.times)
+		s.times = append(s.times, time.Now())
+		s.bodies = append(s.bodies, body)
+		s.headers = append(s.headers, r.Header.Clone())
+		status := s.statuses[min(n, len(s.statuses)-1)]
+		s.mu.Unlock()
+
+		w.WriteHeader(status)
+	}))
+	t.Cleanup(s.Close)
+	return s
+}
+
+func (s *webhookStub) requests() int {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return len(s.times)
+}
+
+// deliveries returns the logged deliveries, oldest first.
+func deliveries(t *testing.T, db *Database) []WebhookDelivery {
+	t.Helper()
+	dl, err := db.GetWebhookDeliveries(100)
+	if err != nil {
+		t.Fatal(err)
+	}
+	slices.Reverse(dl)
+	return dl
+}
+
+func TestWebhookSignature(t *testing.T) {
+	db := newTestDatabase(t)
+	stub := newWebhookStub(t, http.StatusOK)
+
+	w := NewWebhookDispatcher(db, []WebhookConfig{{URL: stub.URL, Secret: "s3cret"}})
+	w.Dispatch(Event{Type: EventHabitCompleted, HabitID: 1, HabitName: "Read"})
+	w.Close()
+
+	if stub.requests() != 1 {
+		t.Fatalf("got %d requests, want 1", stub.requests())
+	}
+	mac := hmac.New(sha256.New, []byte("s3cret"))
+	mac.Write(stub.bodies[0])
+	want := "sha256=" + hex.EncodeToString(mac.Sum(nil))
+	if got := stub.headers[0].Get("X-Habit-Signature"); got != want {
+		t.Errorf("X-Habit-Signature = %q, want %q", got, want)
+	}
+	if got := stub.headers[0].Get("X-Habit-Event"); got != EventHabitCompleted {
+		t.Errorf("X-Habit-Event = %q, want %q", got, EventHabitCompleted)
+	}
+
+	dl := deliveries(t, db)
+	if len(dl) != 1 || dl[0].Attempt != 1 || dl[0].StatusCode != http.StatusOK || dl[0].Error != "" {
+		t.Errorf("deliveries = %+v, want one successful attempt", dl)
+	}
+}
+
+func TestWebhookUnsignedWithoutSecret(t *testing.T) {
+	db := newTestDatabase(t)
+	stub := newWebhookStub(t, http.StatusOK)
+
+	w := NewWebhookDispatcher(db, []WebhookConfig{{URL: stub.URL}})
+	w.Dispatch(Event{Type: EventPing})
+	w.Close()
+
+	if got := stub.headers[0].Get("X-Habit-Signature"); got != "" {
+		t.Errorf("X-Habit-Signature = %q without a secret, want none", got)
+	}
+}
+
+func TestWebhookRetriesWithBackoff(t *testing.T) {
+	db := newTestDatabase(t)
+	stub := newWebhookStub(t, http.StatusInternalServerError, http.StatusBadGateway, http.StatusOK)
+
+	w := NewWebhookDispatcher(db, []WebhookConfig{{URL: stub.URL, MaxAttempts: 5}})
+	w.backoff = 50 * time.Millisecond
+	w.Dispatch(Event{Type: EventLevelUp})
+	w.wg.Wait()
+	w.Close()
+
+	if stub.requests() != 3 {
+		t.Fatalf("got %d requests, want 3", stub.requests())
+	}
+	first, second := stub.times[1].Sub(stub.times[0]), stub.times[2].Sub(stub.times[1])
+	if first < w.backoff {
+		t.Errorf("first retry after %v, want at least %v", first, w.backoff)
+	}
+	if second < 2*w.backoff {
+		t.Errorf("second retry after %v, want at least %v", second, 2*w.backoff)
+	}
+
+	dl := deliveries(t, db)
+	wantStatus := []int{http.StatusInternalServerError, http.StatusBadGateway, http.StatusOK}
+	if len(dl) != len(wantStatus) {
+		t.Fatalf("got %d deliveries logged, want %d: %+v", len(dl), len(wantStatus), dl)
+	}
+	for i, d := range dl {
+		if d.Attempt != i+1 || d.StatusCode != wantStatus[i] || d.Event != EventLevelUp || d.URL != stub.URL {
+			t.Errorf("delivery %d = %+v, want attempt %d with status %d", i, d, i+1, wantStatus[i])
+		}
+		if failed := d.Error != ""; failed != (wantStatus[i] != http.StatusOK) {
+			t.Errorf("delivery %d error = %q", i, d.Error)
+		}
+	}
+}
+
+func TestWebhookGivesUpAfterMaxAttempts(t *testing.T) {
+	db := newTestDatabase(t)
+	stub := newWebhookStub(t, http.StatusServiceUnavailable)
+
+	w := NewWebhookDispatcher(db, []WebhookConfig{{URL: stub.URL, MaxAttempts: 2}})
+	w.backoff = time.Millisecond
+	w.Dispatch(Event{Type: EventPing})
+	w.wg.Wait()
+	w.Close()
+
+	if stub.requests() != 2 {
+		t.Errorf("got %d requests, want 2", stub.requests())
+	}
+	if dl := deliveries(t, db); len(dl) != 2 || dl[1].StatusCode != http.StatusServiceUnavailable {
+		t.Errorf("deliveries = %+v, want two failed attempts", dl)
+	}
+}
+
+func TestWebhookCloseLogsAbandonedRetries(t *testing.T) {
+	db := newTestDatabase(t)
+	stub := newWebhookStub(t, http.StatusInternalServerError)
+
+	w := NewWebhookDispatcher(db, []WebhookConfig{{URL: stub.URL}})
+	w.backoff = time.Hour
+	w.Dispatch(Event{Type: EventPing})
+	for stub.requests() == 0 {
+		time.Sleep(time.Millisecond)
+	}
+
+	closed := make(chan struct{})
+	go func() {
+		w.Close()
+		close(closed)
+	}()
+	select {
+	case <-closed:
+	case <-time.After(5 * time.Second):
+		t.Fatal("Close did not return while a retry was pending")
+	}
+
+	dl := deliveries(t, db)
+	if len(dl) != 2 {
+		t.Fatalf("got %d deliveries logged, want 2: %+v", len(dl), dl)
+	}
+	if dl[1].Attempt != 2 || dl[1].StatusCode != 0 || dl[1].Error != errWebhookAbandoned.Error() {
+		t.Errorf("last delivery = %+v, want attempt 2 logged as abandoned", dl[1])
+	}
+}
+
+func TestWebhookEventFilter(t *testing.T) {
+	db := newTestDatabase(t)
+	stub := newWebhookStub(t, http.StatusOK)
+
+	w := NewWebhookDispatcher(db, []WebhookConfig{{URL: stub.URL, Events: []string{EventLevelUp}}})
+	w.Dispatch(Event{Type: EventHabitCompleted})
+	w.Dispatch(Event{Type: EventLevelUp})
+	w.Dispatch(Event{Type: EventPing})
+	w.Close()
+
+	if stub.requests() != 2 {
+		t.Errorf("got %d requests, want 2 (level.up and ping)", stub.requests())
+	}
+}